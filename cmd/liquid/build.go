@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/osteele/liquid"
+)
+
+// build renders the templates in a directory tree into a mirrored output tree.
+func build(flags *flag.FlagSet, args []string) error {
+	b := addBuildFlags(flags)
+	jobs := flags.Int("j", runtime.NumCPU(), "the number of templates to render in parallel")
+
+	if err := b.parse(flags, args); err != nil {
+		return err
+	}
+
+	// The engine is shared, so that included templates are read once.
+	b.engine.RegisterTemplateStore(&cachingTemplateStore{files: map[string]cachedTemplate{}})
+
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(files))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(*jobs, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				errs[i] = b.render(files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+
+	failed := 0
+
+	for _, err := range errs {
+		if err != nil {
+			fmt.Fprintln(stderr, err) //nolint:errcheck
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d templates failed", failed, len(files))
+	}
+
+	return nil
+}
+
+// parseInterspersed parses flags that can follow positional arguments, as in
+// `liquid build src -o out`, after it applies the config file to them. It returns
+// the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	if err := applyConfigFile(flags); err != nil {
+		return nil, err
+	}
+
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// A builder renders a directory tree.
+type builder struct {
+	srcDir, outDir, includesDir string
+	include, exclude            globsFlag
+
+	engine *liquid.Engine
+	vars   map[string]any
+}
+
+// addBuildFlags adds the flags that select the templates, the output directory,
+// and the includes directory to flags. It returns a builder that they configure.
+func addBuildFlags(flags *flag.FlagSet) *builder {
+	b := &builder{}
+	flags.StringVar(&b.outDir, "o", "", "the output `directory`")
+	flags.StringVar(&b.includesDir, "includes", "", "a `directory` that {% include %} searches for templates; it isn't rendered")
+	flags.Var(&b.include, "include", "render only the files whose paths match `glob`. This can be repeated.\nA glob without a / matches file names; ** matches any number of directories.")
+	flags.Var(&b.exclude, "exclude", "don't render the files or directories whose paths match `glob`. This can be repeated.")
+
+	return b
+}
+
+// parse parses args, which name the source directory, and creates the builder's
+// engine and bindings.
+func (b *builder) parse(flags *flag.FlagSet, args []string) error {
+	dirs, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(dirs) != 1 || b.outDir == "" {
+		flags.Usage()
+		return errors.New("expected a source directory, and an output directory")
+	}
+
+	b.srcDir = dirs[0]
+
+	b.engine = newEngine()
+	if b.includesDir != "" {
+		b.engine.SetIncludePaths(append([]string{b.includesDir}, includePaths...)...)
+	}
+
+	return b.loadBindings()
+}
+
+// loadBindings reads the bindings from the binding flags.
+func (b *builder) loadBindings() error {
+	vars := map[string]any{}
+	for k, v := range bindings {
+		vars[k] = v
+	}
+
+	if err := applyBindingFlags(vars, ""); err != nil {
+		return err
+	}
+
+	b.vars = vars
+
+	return nil
+}
+
+// files returns the paths, relative to the source directory, of the templates to render.
+func (b *builder) files() ([]string, error) {
+	skip := map[string]bool{}
+
+	for _, dir := range []string{b.includesDir, b.outDir} {
+		if dir == "" {
+			continue
+		}
+
+		if abs, err := filepath.Abs(dir); err == nil {
+			skip[abs] = true
+		}
+	}
+
+	var files []string
+
+	err := filepath.WalkDir(b.srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(b.srcDir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if abs, err := filepath.Abs(p); err == nil && skip[abs] || b.exclude.match(name) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if (len(b.include) == 0 || b.include.match(name)) && !b.exclude.match(name) {
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// render renders the template at rel, relative to the source directory, into the
+// output directory. Files that aren't templates, according to renders, are copied.
+func (b *builder) render(rel string) error {
+	src := filepath.Join(b.srcDir, rel)
+
+	source, err := os.ReadFile(src) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	out := source
+
+	if renders(rel, source) {
+		tpl, err := b.engine.ParseTemplateLocation(source, src, 1)
+		if err != nil {
+			return err
+		}
+
+		if out, err = tpl.Render(b.vars); err != nil {
+			return err
+		}
+	}
+
+	dst := filepath.Join(b.outDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil { // #nosec G301
+		return err
+	}
+
+	return os.WriteFile(dst, out, 0o644) // #nosec G306
+}
+
+// renders returns true if the file at rel, whose contents are source, is a template:
+// a .liquid or .html file, or a file that begins with front matter. Other files, such
+// as images, stylesheets and scripts, are copied as they are.
+func renders(rel string, source []byte) bool {
+	switch strings.ToLower(filepath.Ext(rel)) {
+	case ".liquid", ".html", ".htm":
+		return true
+	}
+
+	return bytes.HasPrefix(source, []byte("---\n")) || bytes.HasPrefix(source, []byte("---\r\n"))
+}
+
+// A globsFlag is a repeatable flag whose values are glob patterns.
+type globsFlag []string
+
+func (g *globsFlag) String() string { return strings.Join(*g, ",") }
+
+func (g *globsFlag) Set(pattern string) error {
+	if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+		return fmt.Errorf("%s: %w", pattern, err)
+	}
+
+	*g = append(*g, pattern)
+
+	return nil
+}
+
+// match reports whether the slash-separated path name matches any of the patterns.
+// A pattern without a / matches the last element of name. Otherwise, it matches the
+// whole of name, and a ** element matches any number of elements.
+func (g globsFlag) match(name string) bool {
+	for _, pattern := range g {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(name)); ok {
+				return true
+			}
+		} else if matchElements(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := range len(name) + 1 {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// A cachingTemplateStore is a render.TemplateStore that reads each file once.
+// It is safe for concurrent use.
+type cachingTemplateStore struct {
+	mu    sync.Mutex
+	files map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	source []byte
+	err    error
+}
+
+func (s *cachingTemplateStore) ReadTemplate(filename string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.files[filename]
+	if !ok {
+		t.source, t.err = os.ReadFile(filename) // #nosec G304
+		s.files[filename] = t
+	}
+
+	return t.source, t.err
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		exit = os.Exit
+		frontMatter = false
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	out := t.TempDir()
+	os.Args = []string{"liquid", "build", "testdata/build/src", "-o", out,
+		"--includes", "testdata/build/src/_includes", "--data", "testdata/build/site.yaml",
+		"--front-matter", "--exclude", "drafts", "--include", "*.html"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(out, name)) // #nosec G304
+		require.NoError(t, err)
+
+		return string(b)
+	}
+
+	require.Equal(t, "<nav>My Site</nav>\n<h1>My Site</h1>\n", read("index.html"))
+	require.Equal(t, "<nav>My Site</nav>\n<h2>First</h2>\n", read("posts/first.html"))
+
+	for _, name := range []string{"_includes", "drafts", "notes.txt"} {
+		require.NoFileExists(t, filepath.Join(out, name))
+		require.NoDirExists(t, filepath.Join(out, name))
+	}
+
+	// errors are all reported
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a.html"), []byte("{{ x | nofilter }}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "b.html"), []byte("ok"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "c.html"), []byte("{% include 'missing.html' %}"), 0o600))
+
+	buf := &bytes.Buffer{}
+	stderr = buf
+	os.Args = []string{"liquid", "build", "-o", out, "-j", "2", src}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), "a.html")
+	require.Contains(t, buf.String(), "nofilter")
+	require.Contains(t, buf.String(), "c.html")
+	require.Contains(t, buf.String(), "2 of 3 templates failed")
+	require.Equal(t, "ok", read("b.html"))
+
+	// files that aren't templates are copied byte for byte
+	exitCode = -1
+	src = t.TempDir()
+	out = t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n{% \xff\x00 {{")
+	require.NoError(t, os.WriteFile(filepath.Join(src, "logo.png"), png, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "notes.txt"), []byte("{{ x }}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "style.css"), []byte("---\ncolor: red\n---\na { color: {{ page.color }} }"), 0o600))
+	os.Args = []string{"liquid", "build", "--front-matter", src, "-o", out}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Equal(t, string(png), read("logo.png"))
+	require.Equal(t, "{{ x }}", read("notes.txt"))
+	require.Equal(t, "a { color: red }", read("style.css"))
+
+	// usage
+	exitCode = -1
+	buf.Reset()
+	os.Args = []string{"liquid", "build", src}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), "usage:")
+}
+
+func TestGlobsFlag_match(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"*.html", "index.html", true},
+		{"*.html", "posts/a.html", true},
+		{"*.html", "a.txt", false},
+		{"posts/*.html", "posts/a.html", true},
+		{"posts/*.html", "posts/2024/a.html", false},
+		{"posts/**/*.html", "posts/a.html", true},
+		{"posts/**/*.html", "posts/2024/01/a.html", true},
+		{"**/drafts", "drafts", true},
+		{"**/drafts", "posts/drafts", true},
+		{"posts/**", "posts", true},
+		{"posts/**", "pages/a.html", false},
+	}
+	for _, test := range tests {
+		g := globsFlag{test.pattern}
+		require.Equal(t, test.match, g.match(test.name), "%s %s", test.pattern, test.name)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/osteele/liquid"
+	"github.com/osteele/liquid/render"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// configFileName is the name of the file that sets the default options for a project.
+const configFileName = ".liquidrc.yaml"
+
+// for testing
+var getwd = os.Getwd
+
+// the engine options that aren't booleans
+var (
+	delims       []string // the object and tag delimiters, or nil for the defaults
+	autoescape   bool     // whether to escape HTML in the output of {{ }}
+	includePaths []string
+)
+
+// addEngineFlags adds the flags that configure the engine to flags.
+func addEngineFlags(flags *flag.FlagSet) {
+	delims, autoescape, includePaths = nil, false, nil
+
+	flags.BoolVar(&strictVars, "strict", false, "enable strict variable mode in templates")
+	flags.BoolVar(&frontMatter, "front-matter", false, "remove YAML front matter from templates, and bind it to page")
+	flags.BoolVar(&jekyll, "jekyll", false, "enable Jekyll extensions, such as {% assign page.title = ... %}")
+	flags.Func("delims", "use `objectLeft,objectRight,tagLeft,tagRight` instead of {{,}},{%,%}", func(s string) error {
+		d := strings.Split(s, ",")
+		if len(d) != 4 || d[0] == "" || d[1] == "" || d[2] == "" || d[3] == "" {
+			return errors.New("expected four comma-separated delimiters")
+		}
+
+		delims = d
+
+		return nil
+	})
+	flags.Func("autoescape", "escape the output of {{ }} as `html`, or don't escape it (none)", func(s string) error {
+		switch s {
+		case "html":
+			autoescape = true
+		case "none":
+			autoescape = false
+		default:
+			return errors.New("expected html or none")
+		}
+
+		return nil
+	})
+	flags.Func("include-path", "search `directory` for templates that {% include %} doesn't find relative to\nthe including template. This can be repeated.", func(s string) error {
+		includePaths = append(includePaths, s)
+		return nil
+	})
+}
+
+// newEngine returns an engine that is configured by the command-line options.
+func newEngine() *liquid.Engine {
+	e := liquid.NewEngine()
+	if strictVars {
+		e.StrictVariables()
+	}
+
+	if frontMatter {
+		e.EnableFrontMatter()
+	}
+
+	if jekyll {
+		e.EnableJekyllExtensions()
+	}
+
+	if delims != nil {
+		e.Delims(delims[0], delims[1], delims[2], delims[3])
+	}
+
+	if autoescape {
+		e.SetAutoEscapeReplacer(render.HtmlEscaper)
+	}
+
+	if len(includePaths) > 0 {
+		e.SetIncludePaths(includePaths...)
+	}
+
+	return e
+}
+
+// parseFlags parses args with flags, after it applies the config file to them.
+// Commands call it once they have added their own flags, so that the config file
+// can set them.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := applyConfigFile(flags); err != nil {
+		return err
+	}
+
+	return flags.Parse(args)
+}
+
+// applyConfigFile sets the options in flags from the nearest .liquidrc.yaml in the
+// working directory or its parents, if there is one. Its keys are option names, such
+// as strict or include-path; a list sets a repeatable option to each of its items.
+// A key that is a command name, such as serve, holds a mapping of the options that
+// only that command reads, and that override the others. Relative paths are relative to the file. The
+// command-line options are parsed after this, so they take precedence.
+func applyConfigFile(flags *flag.FlagSet) error {
+	filename, err := findConfigFile()
+	if filename == "" || err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filename) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	var settings yaml.MapSlice
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	var options, section yaml.MapSlice
+
+	for _, item := range settings {
+		items, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			options = append(options, item)
+		} else if fmt.Sprint(item.Key) == commandName(flags) {
+			section = items
+		}
+	}
+
+	if err := setOptions(flags, filename, options); err != nil {
+		return err
+	}
+
+	return setOptions(flags, filename, section)
+}
+
+// commandName returns the name of the command whose flags are flags, or "" for
+// the top-level flags.
+func commandName(flags *flag.FlagSet) string {
+	if i := strings.LastIndex(flags.Name(), " "); i >= 0 {
+		return flags.Name()[i+1:]
+	}
+
+	return ""
+}
+
+// setOptions sets the options in flags from the settings in the config file filename.
+func setOptions(flags *flag.FlagSet, filename string, settings yaml.MapSlice) error {
+	for _, item := range settings {
+		name := fmt.Sprint(item.Key)
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown option %q", filename, name)
+		}
+
+		values, ok := item.Value.([]any)
+		if !ok {
+			values = []any{item.Value}
+		}
+
+		for _, v := range values {
+			value := fmt.Sprint(v)
+			if (name == "include-path" || name == "data") && value != "-" && !filepath.IsAbs(value) {
+				value = filepath.Join(filepath.Dir(filename), value)
+			}
+
+			if name == "data-dir" {
+				if key, dir := splitDataDir(value); !filepath.IsAbs(dir) {
+					value = key + "=" + filepath.Join(filepath.Dir(filename), dir)
+				}
+			}
+
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("%s: %s: %w", filename, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findConfigFile returns the path of the nearest config file in the working directory
+// or its parents, or "" if there isn't one.
+func findConfigFile() (string, error) {
+	dir, err := getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		filename := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(filename); err == nil {
+			return filename, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineFlags(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+		bindings = map[string]any{}
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	tests := []struct {
+		args      []string
+		src, want string
+	}{
+		{[]string{"--delims", "<<,>>,<%,%>"}, `<< "a" | upcase >> <% if true %>b<% endif %> {{ x }}`, "A b {{ x }}"},
+		{[]string{"--autoescape=html"}, `{{ "<b>" }}{{ "<i>" | safe }}`, "&lt;b&gt;<i>"},
+		{[]string{"--autoescape=none"}, `{{ "<b>" }}`, "<b>"},
+		{[]string{"--jekyll"}, `{% assign page.title = "T" %}{{ page.title }}`, "T"},
+		{[]string{"--include-path", "testdata/config/partials", "--var", "name=World"}, `{% include "greeting.liquid" %}`, "Hello, World!"},
+	}
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		stdout = buf
+		stdin = strings.NewReader(test.src)
+		os.Args = append([]string{"liquid"}, test.args...)
+
+		main()
+		require.Equal(t, -1, exitCode, test.args)
+		require.Equal(t, test.want, buf.String(), test.args)
+		bindings = map[string]any{}
+	}
+
+	// errors
+	for _, args := range [][]string{{"--delims", "<<,>>"}, {"--autoescape", "xml"}} {
+		buf := &bytes.Buffer{}
+		stderr = buf
+		stdin = strings.NewReader("")
+		os.Args = append([]string{"liquid"}, args...)
+
+		main()
+		require.Equal(t, 1, exitCode, args)
+		require.Contains(t, buf.String(), "expected", args)
+
+		exitCode = -1
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+		getwd = os.Getwd
+		bindings = map[string]any{}
+		strictVars, jekyll = false, false
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	dir, err := filepath.Abs("testdata/config")
+	require.NoError(t, err)
+
+	// the config file is found in a parent directory
+	getwd = func() (string, error) { return filepath.Join(dir, "partials"), nil }
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	stdin = strings.NewReader(`{% include "greeting.liquid" %} {% assign page.x = 1 %}{{ page.x }}`)
+	os.Args = []string{"liquid"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Equal(t, "Hello, Config! 1", buf.String())
+	require.True(t, strictVars)
+
+	// command-line options take precedence
+	buf = &bytes.Buffer{}
+	stdout = buf
+	stdin = strings.NewReader(`{{ undefined }}`)
+	os.Args = []string{"liquid", "--strict=false"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Equal(t, "", buf.String())
+
+	// unknown options are reported
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, configFileName), []byte("bogus: 1\n"), 0o600))
+
+	getwd = func() (string, error) { return tmp, nil }
+	buf = &bytes.Buffer{}
+	stderr = buf
+	os.Args = []string{"liquid", "profile", "x.liquid"}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), `unknown option "bogus"`)
+}
+
+func TestConfigFile_commandOptions(t *testing.T) {
+	defer func() {
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		getwd = os.Getwd
+		strictVars = false
+	}()
+
+	tmp := t.TempDir()
+	getwd = func() (string, error) { return tmp, nil }
+
+	run := func(config string, args ...string) (string, error) {
+		require.NoError(t, os.WriteFile(filepath.Join(tmp, configFileName), []byte(config), 0o600))
+
+		buf := &bytes.Buffer{}
+		stdout = buf
+		stdin = strings.NewReader("{{ x }}")
+		err := commands["parse"].run(newFlagSet("liquid parse", commands["parse"]), args)
+
+		return buf.String(), err
+	}
+
+	// a command's option, at the top level or in the command's section, prints
+	// the tokens, which are a list
+	out, err := run("tokens: true\n")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "["), out)
+
+	out, err = run("strict: true\nparse:\n  tokens: true\nfmt:\n  w: true\n")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "["), out)
+	require.True(t, strictVars)
+
+	// command-line options take precedence
+	out, err = run("parse:\n  tokens: true\n", "--tokens=false")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "{"), out)
+
+	_, err = run("parse:\n  bogus: true\n")
+	require.ErrorContains(t, err, `unknown option "bogus"`)
+}
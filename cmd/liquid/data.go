@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/osteele/liquid"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A bindingSource is a --data, --data-dir, --var, or --var-json flag.
+type bindingSource struct {
+	flag, value string
+}
+
+// the binding flags, in command-line order
+var bindingSources []bindingSource
+
+// addBindingFlags adds the flags that bind variables to flags.
+func addBindingFlags(flags *flag.FlagSet) {
+	bindingSources = nil
+
+	add := func(name string) func(string) error {
+		return func(value string) error {
+			if name != "data" && name != "data-dir" && !strings.Contains(value, "=") {
+				return errors.New("expected key=value")
+			}
+
+			bindingSources = append(bindingSources, bindingSource{name, value})
+
+			return nil
+		}
+	}
+
+	flags.Func("data", "bind the variables in a JSON or YAML `file`, or read them from stdin if this is -.\nThis can be repeated; later files are deep-merged into earlier ones.", add("data"))
+	flags.Func("data-dir", "bind the data files in a `[name=]dir` to the variable name, which can be a dotted path,\nas in site.data=_data. The default name is data. Files are named by their paths in the directory,\nwithout their extensions; YAML, JSON, CSV and TSV files are read. This can be repeated.", add("data-dir"))
+	flags.Func("var", "bind `name=value`, where the value is a string and the name can be a dotted path,\nas in page.title=Home. This can be repeated.", add("var"))
+	flags.Func("var-json", "bind `name=json`, where the value is JSON, as in tags='[\"a\", \"b\"]'. This can be repeated.", add("var-json"))
+}
+
+// applyBindingFlags merges the variables from the binding flags into vars.
+// stdinUse describes what the command reads from stdin, such as "the template",
+// in which case data can't be read from it; or it is empty.
+func applyBindingFlags(vars map[string]any, stdinUse string) error {
+	for _, s := range bindingSources {
+		var value map[string]any
+
+		switch s.flag {
+		case "data":
+			if s.value == "-" && stdinUse != "" {
+				return fmt.Errorf("--data - can't be used, since stdin is read for %s", stdinUse)
+			}
+
+			v, err := readDataFile(s.value)
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "data-dir":
+			key, dir := splitDataDir(s.value)
+
+			v, err := liquid.LoadData(os.DirFS(dir), ".")
+			if err != nil {
+				return fmt.Errorf("--data-dir %s: %w", dir, err)
+			}
+
+			value = nestedBinding(strings.Split(key, "."), v)
+		case "var", "var-json":
+			key, text, _ := strings.Cut(s.value, "=")
+
+			var v any = text
+			if s.flag == "var-json" {
+				if err := json.Unmarshal([]byte(text), &v); err != nil {
+					return fmt.Errorf("--var-json %s: %w", key, err)
+				}
+			}
+
+			value = nestedBinding(strings.Split(key, "."), v)
+		}
+
+		deepMerge(vars, value)
+	}
+
+	return nil
+}
+
+// splitDataDir returns the variable name and the directory of a --data-dir flag.
+func splitDataDir(value string) (name, dir string) {
+	if name, dir, ok := strings.Cut(value, "="); ok {
+		return name, dir
+	}
+
+	return "data", value
+}
+
+// readDataFile reads variable bindings from a data file, or from stdin if filename
+// is "-", with liquid.DataLoader. Files with a .json extension are read as JSON.
+// Others, and stdin, are read as YAML, which is a superset of JSON.
+func readDataFile(filename string) (map[string]any, error) {
+	if filename == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := liquid.DecodeYAMLBindings(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+
+		return m, nil
+	}
+
+	loader := liquid.DataLoader{}
+	if ext := filepath.Ext(filename); !strings.EqualFold(ext, ".json") {
+		loader.Decoders = map[string]liquid.DataDecoder{ext: liquid.DecodeYAML}
+	}
+
+	// Read relative paths from the working directory, so that errors name them
+	// as they were given.
+	fsys, name := os.DirFS("."), filepath.ToSlash(filename)
+	if !fs.ValidPath(name) {
+		fsys, name = os.DirFS(filepath.Dir(filename)), filepath.Base(filename)
+	}
+
+	return loader.LoadBindings(fsys, name)
+}
+
+// nestedBinding returns a map that binds the dotted path to value.
+func nestedBinding(path []string, value any) map[string]any {
+	m := map[string]any{path[len(path)-1]: value}
+	for i := len(path) - 2; i >= 0; i-- {
+		m = map[string]any{path[i]: m}
+	}
+
+	return m
+}
+
+// deepMerge merges src into dst. Mappings are merged recursively; other values in
+// src replace those in dst.
+func deepMerge(dst, src map[string]any) {
+	for k, v := range src {
+		dst[k] = mergeValue(dst[k], v)
+	}
+}
+
+// mergeValue returns src merged into dst. If both are mappings, which are maps, or
+// the yaml.MapSlice values that YAML data is read as, src's entries are merged into
+// dst, which keeps the order of its keys. Otherwise src replaces dst.
+func mergeValue(dst, src any) any {
+	items, ok := mappingItems(src)
+	if !ok {
+		return src
+	}
+
+	switch d := dst.(type) {
+	case map[string]any:
+		for _, item := range items {
+			k := fmt.Sprint(item.Key)
+			d[k] = mergeValue(d[k], item.Value)
+		}
+
+		return d
+	case yaml.MapSlice:
+	items:
+		for _, item := range items {
+			for i := range d {
+				if fmt.Sprint(d[i].Key) == fmt.Sprint(item.Key) {
+					d[i].Value = mergeValue(d[i].Value, item.Value)
+					continue items
+				}
+			}
+
+			d = append(d, item)
+		}
+
+		return d
+	default:
+		return src
+	}
+}
+
+// mappingItems returns the entries of a map, in key order, or of a yaml.MapSlice.
+func mappingItems(value any) (yaml.MapSlice, bool) {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		return value, true
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		items := make(yaml.MapSlice, len(keys))
+		for i, k := range keys {
+			items[i] = yaml.MapItem{Key: k, Value: value[k]}
+		}
+
+		return items, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestBindingFlags(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+		bindings = map[string]any{}
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	run := func(input string, args ...string) (string, string) {
+		out, log := &bytes.Buffer{}, &bytes.Buffer{}
+		stdout, stderr = out, log
+		stdin = bytes.NewBufferString(input)
+		bindings = map[string]any{}
+		exitCode = -1
+		os.Args = append([]string{"liquid"}, args...)
+
+		main()
+
+		return out.String(), log.String()
+	}
+
+	// data files are deep-merged, in order
+	out, log := run("", "--data", "testdata/data/site.yaml", "--data", "testdata/data/page.json", "testdata/data/page.liquid")
+	require.Equal(t, -1, exitCode, log)
+	require.Equal(t, "My Site|home,about|About|default|1|0\n", out)
+
+	// YAML mappings keep their order, when merged with other data
+	out, log = run(`{% for e in page %}{{ e[0] }}={{ e[1] }},{% endfor %}`, "--data", "testdata/data/site.yaml", "--var", "page.title=Home", "--var", "page.author=Ann")
+	require.Equal(t, -1, exitCode, log)
+	require.Equal(t, "title=Home,layout=default,author=Ann,", out)
+
+	// --var and --var-json
+	out, log = run("", "--data", "testdata/data/site.yaml", "--var", "page.title=Home", "--var-json", "n=41", "--var-json", `tags=["a", "b"]`, "testdata/data/page.liquid")
+	require.Equal(t, -1, exitCode, log)
+	require.Equal(t, "My Site|home,about|Home|default|42|2\n", out)
+
+	// --data - reads stdin
+	out, log = run(`{"site": {"title": "From stdin"}}`, "--data", "-", "testdata/data/page.liquid")
+	require.Equal(t, -1, exitCode, log)
+	require.Equal(t, "From stdin||||1|0\n", out)
+
+	// --data-dir
+	out, log = run("", "--data-dir", "site.data=testdata/data/_data", "--data-dir", "testdata/data/_data", "testdata/data/data_dir.liquid")
+	require.Equal(t, -1, exitCode, log)
+	require.Equal(t, "home=/ about=/about/ Ann:editor Bo:author /\n", out)
+
+	// subcommands
+	out, log = run("", "profile", "--data", "testdata/data/site.yaml", "testdata/data/page.liquid")
+	require.Equal(t, -1, exitCode, log)
+	require.Contains(t, out, "testdata/data/page.liquid:1")
+
+	// errors
+	_, log = run("{{ x }}", "--data", "-")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "stdin is read for the template")
+
+	_, log = run("", "--var", "x", "testdata/data/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "expected key=value")
+
+	_, log = run("", "--var-json", "x=[", "testdata/data/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "--var-json x:")
+
+	_, log = run("", "--data-dir", "testdata/data/missing", "testdata/data/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "--data-dir testdata/data/missing")
+
+	_, log = run("", "--data", "testdata/data/list.yaml", "testdata/data/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "expected a mapping")
+}
+
+func TestDeepMerge(t *testing.T) {
+	dst := map[string]any{"a": map[string]any{"b": 1, "c": 2}, "d": []any{1}}
+	deepMerge(dst, map[string]any{"a": map[string]any{"c": 3, "e": 4}, "d": []any{2}})
+	require.Equal(t, map[string]any{"a": map[string]any{"b": 1, "c": 3, "e": 4}, "d": []any{2}}, dst)
+
+	dst = map[string]any{"a": yaml.MapSlice{{Key: "c", Value: 1}, {Key: "b", Value: yaml.MapSlice{{Key: "x", Value: 1}}}}}
+	deepMerge(dst, map[string]any{"a": map[string]any{"b": map[string]any{"y": 2}, "a": 3}})
+	require.Equal(t, map[string]any{"a": yaml.MapSlice{
+		{Key: "c", Value: 1},
+		{Key: "b", Value: yaml.MapSlice{{Key: "x", Value: 1}, {Key: "y", Value: 2}}},
+		{Key: "a", Value: 3},
+	}}, dst)
+}
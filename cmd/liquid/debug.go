@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/osteele/liquid/parser"
+	"github.com/osteele/liquid/render"
+)
+
+const debugHelp = `Commands:
+  s, step             stop at the next tag or object
+  n, next             stop at the next tag or object that isn't inside this one
+  o, out              stop at the next tag or object that isn't inside the enclosing one
+  c, continue         run until a breakpoint
+  b, break [[FILE:]LINE]
+                      set a breakpoint, or list the breakpoints
+  clear [FILE:]LINE   delete a breakpoint
+  p, print EXPR       evaluate a Liquid expression, e.g. p product.price | times: 2
+  v, vars             list the variables
+  l, list             list the source around the current line
+  q, quit             stop rendering
+`
+
+var errQuit = errors.New("quit")
+
+// debug renders a template in a step debugger. The debugger reads commands from
+// stdin and writes to stderr, so that the rendered output can be redirected.
+func debug(flags *flag.FlagSet, args []string) error {
+	var breakpoints []string
+
+	flags.Func("b", "set a breakpoint at `[FILE:]LINE`, and run until it; this can be repeated", func(s string) error {
+		breakpoints = append(breakpoints, s)
+		return nil
+	})
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	tpl, vars, err := loadTemplate(flags, "debugger commands")
+	if err != nil {
+		return err
+	}
+
+	d := &debugger{
+		in:          bufio.NewScanner(stdin),
+		out:         stderr,
+		file:        flags.Arg(0),
+		breakpoints: map[string]bool{},
+		sources:     map[string][]string{},
+	}
+	if len(breakpoints) == 0 {
+		d.mode = stepMode
+	}
+
+	for _, b := range breakpoints {
+		if err := d.setBreakpoint(b, true); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(d.out, "Type h for help.") //nolint:errcheck
+
+	out, err := tpl.RenderWithDebugger(vars, d)
+	if err != nil {
+		if isCause(err, errQuit) {
+			return nil
+		}
+
+		return err
+	}
+
+	_, err = stdout.Write(out)
+
+	return err
+}
+
+// isCause returns a bool indicating whether target is in the chain of causes of err.
+func isCause(err, target error) bool {
+	for err != nil {
+		if err == target {
+			return true
+		}
+
+		e, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+
+		err = e.Cause()
+	}
+
+	return false
+}
+
+type debugMode int
+
+const (
+	runMode  debugMode = iota // stop only at breakpoints
+	stepMode                  // stop at every tag and object
+	nextMode                  // stop at tags and objects at depth or less
+	outMode                   // stop at tags and objects at less than depth
+)
+
+// A debugger is a render.Debugger with a command-line interface.
+type debugger struct {
+	in          *bufio.Scanner
+	out         io.Writer
+	file        string // the template file, for breakpoints without a file
+	mode        debugMode
+	depth       int
+	breakpoints map[string]bool     // "file:line"
+	lastLoc     parser.SourceLoc    // the location of the previous node
+	sources     map[string][]string // the lines of the template files
+}
+
+// Break implements render.Debugger.
+func (d *debugger) Break(s render.DebugState) error {
+	if !d.shouldStop(s) {
+		d.lastLoc = s.SourceLoc
+		return nil
+	}
+
+	d.lastLoc = s.SourceLoc
+	fmt.Fprintf(d.out, "%s: %s\n", s.SourceLoc, s.Node.SourceText()) //nolint:errcheck
+
+	for {
+		fmt.Fprint(d.out, "(liquid) ") //nolint:errcheck
+
+		if !d.in.Scan() {
+			// Without any more commands, render the rest of the template.
+			fmt.Fprintln(d.out) //nolint:errcheck
+
+			d.mode = runMode
+			d.breakpoints = map[string]bool{}
+
+			return nil
+		}
+
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(d.in.Text()), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch cmd {
+		case "":
+		case "s", "step":
+			d.mode = stepMode
+			return nil
+		case "n", "next":
+			d.mode, d.depth = nextMode, s.Depth
+			return nil
+		case "o", "out":
+			d.mode, d.depth = outMode, s.Depth
+			return nil
+		case "c", "continue":
+			d.mode = runMode
+			return nil
+		case "b", "break":
+			if arg == "" {
+				d.listBreakpoints()
+			} else if err := d.setBreakpoint(arg, true); err != nil {
+				fmt.Fprintln(d.out, err) //nolint:errcheck
+			}
+		case "clear":
+			if err := d.setBreakpoint(arg, false); err != nil {
+				fmt.Fprintln(d.out, err) //nolint:errcheck
+			}
+		case "p", "print":
+			value, err := s.Evaluate(arg)
+			if err != nil {
+				fmt.Fprintln(d.out, err) //nolint:errcheck
+			} else {
+				fmt.Fprintln(d.out, inspect(value)) //nolint:errcheck
+			}
+		case "v", "vars":
+			d.listVariables(s.Bindings())
+		case "l", "list":
+			d.listSource(s.SourceLoc)
+		case "q", "quit":
+			return errQuit
+		case "h", "help", "?":
+			fmt.Fprint(d.out, debugHelp) //nolint:errcheck
+		default:
+			fmt.Fprintf(d.out, "unknown command %q; type h for help\n", cmd) //nolint:errcheck
+		}
+	}
+}
+
+// shouldStop returns a bool indicating whether to stop before the node of s.
+func (d *debugger) shouldStop(s render.DebugState) bool {
+	// Stop at the first node on a breakpoint line.
+	if d.breakpoints[s.SourceLoc.String()] && s.SourceLoc != d.lastLoc {
+		return true
+	}
+
+	if _, isText := s.Node.(*render.TextNode); isText {
+		return false
+	}
+
+	switch d.mode {
+	case stepMode:
+		return true
+	case nextMode:
+		return s.Depth <= d.depth
+	case outMode:
+		return s.Depth < d.depth
+	default:
+		return false
+	}
+}
+
+// setBreakpoint sets or clears a breakpoint at a [FILE:]LINE location.
+func (d *debugger) setBreakpoint(loc string, set bool) error {
+	file, line := d.file, loc
+	if i := strings.LastIndex(loc, ":"); i >= 0 {
+		file, line = loc[:i], loc[i+1:]
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid breakpoint %q; expected [FILE:]LINE", loc)
+	}
+
+	key := parser.SourceLoc{Pathname: file, LineNo: n}.String()
+	if set {
+		d.breakpoints[key] = true
+	} else {
+		delete(d.breakpoints, key)
+	}
+
+	return nil
+}
+
+func (d *debugger) listBreakpoints() {
+	keys := make([]string, 0, len(d.breakpoints))
+	for k := range d.breakpoints {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintln(d.out, k) //nolint:errcheck
+	}
+}
+
+func (d *debugger) listVariables(bindings map[string]any) {
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(d.out, "%s = %s\n", name, inspect(bindings[name])) //nolint:errcheck
+	}
+}
+
+// listSource lists the source lines around loc.
+func (d *debugger) listSource(loc parser.SourceLoc) {
+	lines, ok := d.sources[loc.Pathname]
+	if !ok {
+		data, err := os.ReadFile(loc.Pathname)
+		if err != nil {
+			fmt.Fprintln(d.out, err) //nolint:errcheck
+			return
+		}
+
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		d.sources[loc.Pathname] = lines
+	}
+
+	for i := max(loc.LineNo-3, 1); i <= min(loc.LineNo+3, len(lines)); i++ {
+		marker := " "
+		if i == loc.LineNo {
+			marker = ">"
+		}
+
+		fmt.Fprintf(d.out, "%s%4d  %s\n", marker, i, lines[i-1]) //nolint:errcheck
+	}
+}
+
+// inspect formats a value for the debugger.
+func inspect(value any) string {
+	if b, err := json.Marshal(value); err == nil {
+		return string(b)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebug(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	run := func(commands string, args ...string) (string, string) {
+		out, log := &bytes.Buffer{}, &bytes.Buffer{}
+		stdout, stderr = out, log
+		stdin = bytes.NewBufferString(commands)
+		os.Args = append([]string{"liquid", "debug"}, args...)
+
+		main()
+
+		return out.String(), log.String()
+	}
+
+	// stepping
+	out, log := run("s\ns\np p.title | upcase\nv\nn\nn\nl\nq\n", "testdata/debug/page.liquid", "testdata/debug/data.json")
+	require.Equal(t, -1, exitCode)
+	require.Empty(t, out)
+	require.Contains(t, log, "testdata/debug/page.liquid:1: {% assign total = 0 %}\n")
+	require.Contains(t, log, "testdata/debug/page.liquid:2: {% for p in products %}\n")
+	require.Contains(t, log, "testdata/debug/page.liquid:3: {% assign total = total | plus: p.price %}\n")
+	require.Contains(t, log, `"A"`)
+	require.Contains(t, log, `"index":1,`)
+	require.Contains(t, log, `total = 0`)
+	require.Contains(t, log, "testdata/debug/page.liquid:5: {{ total }}\n")
+	require.Contains(t, log, ">   5  Total: {{ total }}\n")
+
+	// breakpoints, and running to the end
+	out, log = run("p total\nc\np total\n", "-b", "3", "testdata/debug/page.liquid", "testdata/debug/data.json")
+	require.Equal(t, -1, exitCode)
+	require.Equal(t, "\n\n\n\n\n\nTotal: 3\n", out)
+	require.Contains(t, log, "(liquid) 0\n")
+	require.Contains(t, log, "(liquid) 1\n")
+
+	// errors
+	_, log = run("", "-b", "x", "testdata/debug/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "invalid breakpoint")
+
+	// data can't be read from stdin, since the commands are
+	_, log = run("q\n", "--data", "-", "testdata/debug/page.liquid")
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, log, "stdin is read for debugger commands")
+}
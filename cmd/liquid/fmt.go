@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/osteele/liquid/format"
+	"github.com/osteele/liquid/liquidtest"
+)
+
+// formatFiles formats templates; see package format. With no arguments, it
+// formats the standard input to the standard output.
+func formatFiles(flags *flag.FlagSet, args []string) error {
+	write := flags.Bool("w", false, "write the result to the file, instead of to the standard output")
+	diff := flags.Bool("d", false, "print diffs instead of the formatted source")
+	list := flags.Bool("l", false, "list the files whose formatting differs")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		if *write || *list {
+			return errors.New("-w and -l require file arguments")
+		}
+
+		src, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+
+		return formatFile("<standard input>", src, false, *diff, false)
+	}
+
+	var files []string
+
+	for _, arg := range flags.Args() {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(p) == ".liquid" {
+				files = append(files, p)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+
+	for _, path := range files {
+		src, err := os.ReadFile(path) // #nosec G304
+		if err == nil {
+			err = formatFile(path, src, *write, *diff, *list)
+		}
+
+		if err != nil {
+			failed++
+
+			fmt.Fprintln(stderr, err) //nolint:errcheck
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d templates could not be formatted", failed, len(files))
+	}
+
+	return nil
+}
+
+// formatFile formats the template source src, that was read from path. If none
+// of write, diff and list are set, it prints the formatted source.
+func formatFile(path string, src []byte, write, diff, list bool) error {
+	out, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	changed := !bytes.Equal(src, out)
+
+	if list && changed {
+		fmt.Fprintln(stdout, path) //nolint:errcheck
+	}
+
+	if diff && changed {
+		fmt.Fprint(stdout, liquidtest.Diff(path+".orig", path, string(src), string(out))) //nolint:errcheck
+	}
+
+	if write && changed {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(path, out, info.Mode().Perm())
+	}
+
+	if !write && !diff && !list {
+		_, err = stdout.Write(out)
+	}
+
+	return err
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFiles(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	buf := &bytes.Buffer{}
+	stdout, stderr = buf, buf
+
+	// standard input
+	stdin = strings.NewReader("{{x|upcase}}\n")
+	os.Args = []string{"liquid", "fmt"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Equal(t, "{{ x | upcase }}\n", buf.String())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "page.liquid")
+	require.NoError(t, os.WriteFile(path, []byte("{%if x%}y{%endif%}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ok.liquid"), []byte("{{ x }}\n"), 0o600))
+
+	// list and diff
+	buf.Reset()
+	os.Args = []string{"liquid", "fmt", "-l", "-d", dir}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Contains(t, buf.String(), path+"\n")
+	require.NotContains(t, buf.String(), "ok.liquid")
+	require.Contains(t, buf.String(), "-{%if x%}y{%endif%}\n+{% if x %}y{% endif %}\n")
+
+	// write
+	buf.Reset()
+	os.Args = []string{"liquid", "fmt", "-w", dir}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Empty(t, buf.String())
+
+	data, err := os.ReadFile(path) // #nosec G304
+	require.NoError(t, err)
+	require.Equal(t, "{% if x %}y{% endif %}\n", string(data))
+
+	// a syntax error
+	require.NoError(t, os.WriteFile(path, []byte("{% if x %}"), 0o600))
+	buf.Reset()
+	os.Args = []string{"liquid", "fmt", path}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), path+":")
+	require.Contains(t, buf.String(), "1 of 1 templates could not be formatted")
+}
@@ -6,6 +6,9 @@
 //
 //	echo '{{ "Hello " | append: "World" }}' | liquid
 //	liquid source.tpl
+//	liquid --data site.yaml --data page.json --var page.title=Home source.tpl
+//	liquid profile source.tpl data.json
+//	liquid debug source.tpl data.json
 package main
 
 import (
@@ -14,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/osteele/liquid"
@@ -28,24 +32,68 @@ var (
 	env        func() []string = os.Environ
 	bindings   map[string]any  = map[string]any{}
 	strictVars bool
+	// frontMatter enables YAML front matter in templates
+	frontMatter bool
+	jekyll      bool
 )
 
+// A command is a subcommand, such as `liquid profile`.
+type command struct {
+	usage   string // the arguments
+	summary string
+	// run parses args with flags, which the command can add to, with parseFlags,
+	// and runs the command
+	run func(flags *flag.FlagSet, args []string) error
+}
+
+var commands = map[string]command{
+	"build":   {"[OPTIONS] SRC -o OUT", "render the templates in a directory tree into a mirrored output tree", build},
+	"debug":   {"[OPTIONS] FILE [DATA]", "render a template in a step debugger", debug},
+	"fmt":     {"[OPTIONS] [FILE|DIR...]", "format templates, or the standard input, in the canonical style", formatFiles},
+	"repl":    {"[OPTIONS]", "evaluate Liquid expressions and templates interactively", repl},
+	"parse":   {"[OPTIONS] [FILE]", "print the tokens or the syntax tree of a template as JSON", parse},
+	"profile": {"[OPTIONS] FILE [DATA]", "render a template, and print where the time and memory went", profile},
+	"serve":   {"[OPTIONS]", "serve rendered templates over HTTP, for previewing them", serve},
+	"test":    {"[OPTIONS] [DIR...]", "render the *.liquid files with their *.json or *.yaml data, and compare them with their *.expected files", test},
+	"watch":   {"[OPTIONS] SRC -o OUT", "build, and then re-render the outputs whose templates, includes or data change", watch},
+}
+
 func main() {
 	var err error
 
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			runCommand(cmd, os.Args[0]+" "+os.Args[1], os.Args[2:])
+			return
+		}
+	}
+
 	cmdLine := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
 	cmdLine.Usage = func() {
 		fmt.Fprintf(stderr, "usage: %s [OPTIONS] [FILE]\n", cmdLine.Name()) //nolint:errcheck
+		fmt.Fprintf(stderr, "       %s COMMAND [ARGS]\n", cmdLine.Name())   //nolint:errcheck
 		fmt.Fprint(stderr, "\nOPTIONS\n")                                   //nolint:errcheck
 		cmdLine.PrintDefaults()
+		fmt.Fprint(stderr, "\nCOMMANDS\n") //nolint:errcheck
+
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(stderr, "  %s %s\n    \t%s\n", name, commands[name].usage, commands[name].summary) //nolint:errcheck
+		}
 	}
 
 	var bindEnvs bool
 	cmdLine.BoolVar(&bindEnvs, "env", false, "bind environment variables")
-	cmdLine.BoolVar(&strictVars, "strict", false, "enable strict variable mode in templates")
+	addEngineFlags(cmdLine)
+	addBindingFlags(cmdLine)
 
-	err = cmdLine.Parse(os.Args[1:])
-	if err != nil {
+	if err = parseFlags(cmdLine, os.Args[1:]); err != nil {
 		if err == flag.ErrHelp {
 			exit(0)
 			return
@@ -63,17 +111,26 @@ func main() {
 	}
 
 	args := cmdLine.Args()
-	switch len(args) {
-	case 0:
-		// use stdin
-	case 1:
-		stdin, err = os.Open(args[0])
-	default:
+	if len(args) > 1 {
 		err = errors.New("too many arguments")
 	}
 
+	// Read the bindings first, since --data - reads stdin.
 	if err == nil {
-		err = render()
+		stdinUse := "the template"
+		if len(args) == 1 {
+			stdinUse = ""
+		}
+
+		err = applyBindingFlags(bindings, stdinUse)
+	}
+
+	if err == nil && len(args) == 1 {
+		stdin, err = os.Open(args[0])
+	}
+
+	if err == nil {
+		err = renderStdin()
 	}
 
 	if err != nil {
@@ -82,18 +139,80 @@ func main() {
 	}
 }
 
-func render() error {
-	buf, err := io.ReadAll(stdin)
+// runCommand runs a subcommand, and exits if it fails.
+func runCommand(cmd command, name string, args []string) {
+	flags := newFlagSet(name, cmd)
+
+	err := cmd.run(flags, args)
+
+	switch {
+	case err == flag.ErrHelp:
+		exit(0)
+	case err != nil:
+		fmt.Fprintln(stderr, err) //nolint:errcheck
+		exit(1)
+	}
+}
+
+// newFlagSet returns a flag set for a subcommand.
+func newFlagSet(name string, cmd command) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s %s\n\n%s.\n", name, cmd.usage, cmd.summary) //nolint:errcheck
+		fmt.Fprint(stderr, "\nOPTIONS\n")                                          //nolint:errcheck
+		flags.PrintDefaults()
+	}
+	addEngineFlags(flags)
+	addBindingFlags(flags)
+
+	return flags
+}
+
+// loadTemplate parses the template file that is named by the first of the
+// command-line arguments, and returns it with the bindings from the binding
+// flags, and from the data file that is named by the optional second argument.
+// stdinUse is as for applyBindingFlags.
+func loadTemplate(flags *flag.FlagSet, stdinUse string) (*liquid.Template, map[string]any, error) {
+	args := flags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		flags.Usage()
+		return nil, nil, errors.New("expected a template file, and an optional data file")
+	}
+
+	source, err := os.ReadFile(args[0])
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	if len(args) == 2 {
+		bindingSources = append(bindingSources, bindingSource{"data", args[1]})
+	}
+
+	vars := map[string]any{}
+	for k, v := range bindings {
+		vars[k] = v
 	}
 
-	e := liquid.NewEngine()
-	if strictVars {
-		e.StrictVariables()
+	if err := applyBindingFlags(vars, stdinUse); err != nil {
+		return nil, nil, err
+	}
+
+	tpl, err := newEngine().ParseTemplateLocation(source, args[0], 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tpl, vars, nil
+}
+
+func renderStdin() error {
+	buf, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
 	}
 
-	tpl, err := e.ParseTemplate(buf)
+	tpl, err := newEngine().ParseTemplate(buf)
 	if err != nil {
 		return err
 	}
@@ -137,3 +137,31 @@ func TestMain(t *testing.T) {
 	require.Contains(t, buf.String(), "too many")
 	require.Equal(t, 1, exitCode)
 }
+
+func TestFrontMatter(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+		bindings = map[string]any{}
+		frontMatter = false
+	}()
+
+	exit = func(n int) { t.Fatalf("exit(%d) called", n) }
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	os.Args = []string{"liquid", "--front-matter", "testdata/frontmatter.liquid"}
+
+	main()
+	require.Equal(t, "<h1>About</h1> a,b\n", buf.String())
+
+	buf.Reset()
+	os.Args = []string{"liquid", "--front-matter", "--var", "page.title=Home", "testdata/frontmatter.liquid"}
+
+	main()
+	require.Equal(t, "<h1>Home</h1> a,b\n", buf.String())
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// parse prints the tokens or the syntax tree of a template as JSON.
+func parse(flags *flag.FlagSet, args []string) error {
+	tokens := flags.Bool("tokens", false, "print the tokens that the scanner produces")
+	ast := flags.Bool("ast", false, "print the syntax tree (the default)")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *tokens && *ast || flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("expected --tokens or --ast, and an optional template file")
+	}
+
+	var (
+		source []byte
+		err    error
+		path   string
+	)
+
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+		source, err = os.ReadFile(path) // #nosec G304
+	} else {
+		source, err = io.ReadAll(stdin)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var value any
+
+	if *tokens {
+		ts, err := newEngine().ScanTokens(source, path, 1)
+		if err != nil {
+			return err
+		}
+
+		list := make([]tokenJSON, len(ts))
+		for i, t := range ts {
+			list[i] = newTokenJSON(t)
+		}
+
+		value = list
+	} else {
+		root, err := newEngine().ParseAST(source, path, 1)
+		if err != nil {
+			return err
+		}
+
+		value = newASTJSON(root)
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(value)
+}
+
+// A locationJSON is the JSON representation of a source location.
+type locationJSON struct {
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// A tokenJSON is the JSON representation of a parser.Token.
+type tokenJSON struct {
+	Type string `json:"type"` // text, object, tag, trim_left, or trim_right
+	locationJSON
+
+	Name   string `json:"name,omitempty"` // the tag name
+	Args   string `json:"args,omitempty"` // the tag arguments, or the object expression
+	Source string `json:"source,omitempty"`
+}
+
+var tokenTypeNames = map[parser.TokenType]string{
+	parser.TextTokenType:      "text",
+	parser.ObjTokenType:       "object",
+	parser.TagTokenType:       "tag",
+	parser.TrimLeftTokenType:  "trim_left",
+	parser.TrimRightTokenType: "trim_right",
+}
+
+func newTokenJSON(t parser.Token) tokenJSON {
+	return tokenJSON{
+		Type:         tokenTypeNames[t.Type],
+		locationJSON: locationJSON{t.SourceLoc.Pathname, t.SourceLoc.LineNo},
+		Name:         t.Name,
+		Args:         t.Args,
+		Source:       t.Source,
+	}
+}
+
+// An astJSON is the JSON representation of a parser.ASTNode.
+type astJSON struct {
+	Type string `json:"type"` // sequence, text, object, tag, block, raw, comment, or trim
+	locationJSON
+
+	Name    string     `json:"name,omitempty"` // the tag name
+	Args    string     `json:"args,omitempty"` // the tag arguments, or the object expression
+	Source  string     `json:"source,omitempty"`
+	Trim    string     `json:"trim,omitempty"`    // left or right
+	Slices  []string   `json:"slices,omitempty"`  // the contents of a raw or comment tag
+	End     string     `json:"end,omitempty"`     // the source of the end tag of a block
+	Body    []*astJSON `json:"body,omitempty"`    // the children of a sequence, or the body of a block
+	Clauses []*astJSON `json:"clauses,omitempty"` // the else and elsif clauses of a block
+}
+
+func newASTJSON(n parser.ASTNode) *astJSON {
+	switch n := n.(type) {
+	case *parser.ASTSeq:
+		return &astJSON{Type: "sequence", Body: newASTJSONs(n.Children)}
+	case *parser.ASTText:
+		return tokenAST("text", n.Token)
+	case *parser.ASTObject:
+		return tokenAST("object", n.Token)
+	case *parser.ASTTag:
+		return tokenAST("tag", n.Token)
+	case *parser.ASTBlock:
+		j := tokenAST("block", n.Token)
+		j.Body = newASTJSONs(n.Body)
+		j.End = n.End.Source
+
+		for _, c := range n.Clauses {
+			j.Clauses = append(j.Clauses, newASTJSON(c))
+		}
+
+		return j
+	case *parser.ASTRaw:
+		j := tokenAST("raw", n.Token)
+		j.Slices, j.End = n.Slices, n.End.Source
+
+		return j
+	case *parser.ASTComment:
+		j := tokenAST("comment", n.Token)
+		j.Slices, j.End = n.Slices, n.End.Source
+
+		return j
+	case *parser.ASTTrim:
+		trim := "left"
+		if n.TrimDirection == parser.Right {
+			trim = "right"
+		}
+
+		return &astJSON{Type: "trim", Trim: trim}
+	default:
+		panic(fmt.Errorf("unknown AST node type %T", n))
+	}
+}
+
+func newASTJSONs(nodes []parser.ASTNode) []*astJSON {
+	list := make([]*astJSON, len(nodes))
+	for i, n := range nodes {
+		list[i] = newASTJSON(n)
+	}
+
+	return list
+}
+
+func tokenAST(typ string, t parser.Token) *astJSON {
+	return &astJSON{
+		Type:         typ,
+		locationJSON: locationJSON{t.SourceLoc.Pathname, t.SourceLoc.LineNo},
+		Name:         t.Name,
+		Args:         t.Args,
+		Source:       t.Source,
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	src := "a\n{% if x -%}{{ y | upcase }}{% else %}b{% endif %}"
+
+	run := func(args ...string) any {
+		buf := &bytes.Buffer{}
+		stdout = buf
+		stdin = strings.NewReader(src)
+		os.Args = append([]string{"liquid", "parse"}, args...)
+
+		main()
+		require.Equal(t, -1, exitCode)
+
+		var value any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &value))
+
+		return value
+	}
+
+	tokens := run("--tokens")
+	require.Len(t, tokens, 7)
+	require.Equal(t, map[string]any{"type": "text", "line": 1.0, "source": "a\n"}, tokens.([]any)[0])
+	require.Equal(t, map[string]any{"type": "tag", "line": 2.0, "name": "if", "args": "x", "source": "{% if x -%}"}, tokens.([]any)[1])
+	require.Equal(t, map[string]any{"type": "trim_right"}, tokens.([]any)[2])
+	require.Equal(t, "object", tokens.([]any)[3].(map[string]any)["type"])
+	require.Equal(t, "y | upcase", tokens.([]any)[3].(map[string]any)["args"])
+
+	ast := run("--ast").(map[string]any)
+	require.Equal(t, "sequence", ast["type"])
+	block := ast["body"].([]any)[1].(map[string]any)
+	require.Equal(t, "block", block["type"])
+	require.Equal(t, "if", block["name"])
+	require.Equal(t, 2.0, block["line"])
+	require.Equal(t, []any{map[string]any{"type": "trim", "trim": "right"}, map[string]any{"type": "object", "line": 2.0, "args": "y | upcase", "source": "{{ y | upcase }}"}}, block["body"])
+	require.Equal(t, "else", block["clauses"].([]any)[0].(map[string]any)["name"])
+
+	// the AST is the default, and a file can be named
+	ast = run("testdata/source.liquid").(map[string]any)
+	require.Equal(t, "testdata/source.liquid", ast["body"].([]any)[0].(map[string]any)["path"])
+
+	// front matter is removed from the tokens, as from the AST
+	defer func() { frontMatter = false }()
+
+	src = "---\ntitle: T\n---\n{{ page.title }}"
+	tokens = run("--front-matter", "--tokens")
+	require.Len(t, tokens, 1)
+	require.Equal(t, map[string]any{"type": "object", "line": 4.0, "args": "page.title", "source": "{{ page.title }}"}, tokens.([]any)[0])
+
+	ast = run("--front-matter", "--ast").(map[string]any)
+	require.Equal(t, 4.0, ast["body"].([]any)[0].(map[string]any)["line"])
+
+	// errors
+	buf := &bytes.Buffer{}
+	stderr = buf
+	stdin = strings.NewReader("{% if x %}")
+	os.Args = []string{"liquid", "parse"}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), "unterminated")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/osteele/liquid/render"
+)
+
+// profile renders a template, and prints a report of its hot spots.
+func profile(flags *flag.FlagSet, args []string) error {
+	limit := flags.Int("n", 20, "the maximum number of `lines`, filters and includes to list, or 0 for all")
+	memory := flags.Bool("mem", false, "list the allocations of each line, filter and include; this slows the render")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	tpl, vars, err := loadTemplate(flags, "")
+	if err != nil {
+		return err
+	}
+
+	_, p, err := tpl.RenderProfiled(vars, render.ProfileOptions{Memory: *memory})
+	if err != nil {
+		return err
+	}
+
+	return p.WriteReport(stdout, *limit)
+}
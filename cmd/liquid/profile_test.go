@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		exit = os.Exit
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	os.Args = []string{"liquid", "profile", "-n", "0", "testdata/profile/page.liquid", "testdata/profile/data.json"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Contains(t, buf.String(), "Total: ")
+	require.Regexp(t, `\ntestdata/profile/page.liquid:2 +4 `, buf.String())
+	require.Regexp(t, `\ntestdata/profile/item.liquid:1 +4 `, buf.String())
+	require.Regexp(t, `\nupcase +2 `, buf.String())
+	require.Regexp(t, `\ntimes +2 `, buf.String())
+	require.Regexp(t, `\ntestdata/profile/item.liquid +2 `, buf.String())
+	require.NotContains(t, buf.String(), "ALLOCS")
+
+	buf.Reset()
+	os.Args = []string{"liquid", "profile", "--mem", "testdata/profile/page.liquid", "testdata/profile/data.json"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Contains(t, buf.String(), "ALLOCS")
+
+	// errors
+	buf = &bytes.Buffer{}
+	stderr = buf
+	os.Args = []string{"liquid", "profile"}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), "usage:")
+	require.Contains(t, buf.String(), "expected a template file")
+
+	buf = &bytes.Buffer{}
+	stderr = buf
+	os.Args = []string{"liquid", "profile", "testdata/missing_file"}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Regexp(t, "no such file|cannot find the file", buf.String())
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/osteele/liquid"
+)
+
+const replHelp = `Enter a Liquid expression, such as products | map: "title" | join: ", ",
+an {% assign name = expression %} tag, or a template that contains {{ }} or {% %}.
+Variables that are assigned, or loaded, are kept for the following lines.
+
+Commands:
+  :load FILE    bind the variables in a JSON or YAML file
+  :type EXPR    print the type of the value of an expression
+  :vars         list the variables
+  :filters      list the filters
+  :tags         list the tags
+  :help         print this message
+  :quit         exit; so does end of file
+`
+
+var assignPattern = regexp.MustCompile(`^\{%-?\s*assign\s+([^\s=]+)\s*=\s*(.+?)\s*-?%\}$`)
+
+// repl reads Liquid expressions and templates from stdin, and prints their values.
+func repl(flags *flag.FlagSet, args []string) error {
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errors.New("too many arguments")
+	}
+
+	vars := map[string]any{}
+	for k, v := range bindings {
+		vars[k] = v
+	}
+
+	if err := applyBindingFlags(vars, "the REPL's input"); err != nil {
+		return err
+	}
+
+	s := &replSession{engine: newEngine(), vars: vars, out: stdout, errs: stderr}
+	fmt.Fprintln(s.errs, "Type :help for help.") //nolint:errcheck
+
+	in := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(s.errs, "liquid> ") //nolint:errcheck
+
+		if !in.Scan() {
+			fmt.Fprintln(s.errs) //nolint:errcheck
+			return in.Err()
+		}
+
+		if err := s.eval(in.Text()); err != nil {
+			if err == errQuit {
+				return nil
+			}
+
+			fmt.Fprintln(s.errs, err) //nolint:errcheck
+		}
+	}
+}
+
+// A replSession is the state of a REPL.
+type replSession struct {
+	engine    *liquid.Engine
+	vars      map[string]any
+	out, errs io.Writer
+}
+
+// eval evaluates a line of input, and prints its value.
+func (s *replSession) eval(line string) error {
+	line = strings.TrimSpace(line)
+
+	switch {
+	case line == "":
+		return nil
+	case strings.HasPrefix(line, ":"):
+		cmd, arg, _ := strings.Cut(line[1:], " ")
+		return s.command(cmd, strings.TrimSpace(arg))
+	case assignPattern.MatchString(line):
+		m := assignPattern.FindStringSubmatch(line)
+
+		value, err := s.engine.EvaluateString(m[2], s.vars)
+		if err != nil {
+			return err
+		}
+
+		if strings.Contains(m[1], ".") {
+			deepMerge(s.vars, nestedBinding(strings.Split(m[1], "."), value))
+		} else {
+			s.vars[m[1]] = value
+		}
+
+		return nil
+	case strings.Contains(line, "{{") || strings.Contains(line, "{%"):
+		out, err := s.engine.ParseAndRenderString(line, s.vars)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(s.out, out) //nolint:errcheck
+
+		return nil
+	default:
+		value, err := s.engine.EvaluateString(line, s.vars)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(s.out, inspect(value)) //nolint:errcheck
+
+		return nil
+	}
+}
+
+func (s *replSession) command(cmd, arg string) error {
+	switch cmd {
+	case "load":
+		if arg == "" {
+			return errors.New(":load requires a file name")
+		}
+
+		data, err := readDataFile(arg)
+		if err != nil {
+			return err
+		}
+
+		deepMerge(s.vars, data)
+	case "type":
+		value, err := s.engine.EvaluateString(arg+" | type", s.vars)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(s.out, value) //nolint:errcheck
+	case "vars":
+		names := make([]string, 0, len(s.vars))
+		for name := range s.vars {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(s.out, "%s = %s\n", name, inspect(s.vars[name])) //nolint:errcheck
+		}
+	case "filters":
+		fmt.Fprintln(s.out, strings.Join(s.engine.ListFilters(), "\n")) //nolint:errcheck
+	case "tags":
+		fmt.Fprintln(s.out, strings.Join(s.engine.ListTags(), "\n")) //nolint:errcheck
+	case "help", "h", "?":
+		fmt.Fprint(s.out, replHelp) //nolint:errcheck
+	case "quit", "q", "exit":
+		return errQuit
+	default:
+		return fmt.Errorf("unknown command :%s; type :help for help", cmd)
+	}
+
+	return nil
+}
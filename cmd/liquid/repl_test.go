@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepl(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		stdin = os.Stdin
+		exit = os.Exit
+	}()
+
+	exit = func(n int) { t.Fatalf("exit(%d) called", n) }
+
+	input := strings.Join([]string{
+		`{% assign xs = "a,b,c" | split: "," %}`,
+		`xs | size`,
+		`xs | join: "-" | upcase`,
+		`:type xs | size`,
+		`{{ xs | first }}!`,
+		`:load testdata/data/site.yaml`,
+		`site.title`,
+		`{% assign page.title = "Home" %}`,
+		`page.title`,
+		`x | nofilter`,
+		`:filters`,
+		`:bogus`,
+		`:quit`,
+		`xs`,
+	}, "\n")
+
+	out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	stdin = strings.NewReader(input)
+	stdout, stderr = out, errs
+	os.Args = []string{"liquid", "repl", "--var", "n=1"}
+
+	main()
+	require.Equal(t, []string{"3", `"A-B-C"`, "int", "a!", `"My Site"`, `"Home"`},
+		strings.Split(out.String(), "\n")[:6])
+	require.Contains(t, out.String(), "\nupcase\n")
+	require.NotContains(t, out.String(), `["a","b","c"]`)
+	require.Contains(t, errs.String(), `undefined filter "nofilter"`)
+	require.Contains(t, errs.String(), "unknown command :bogus")
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/osteele/liquid"
+)
+
+// serve serves rendered templates over HTTP, for previewing them.
+func serve(flags *flag.FlagSet, args []string) error {
+	root := flags.String("root", ".", "the `directory` of templates to serve")
+	addr := flags.String("addr", "localhost:8080", "the `address` to listen on")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errors.New("too many arguments")
+	}
+
+	s := newServer(*root)
+	fmt.Fprintf(stderr, "Serving %s at http://%s/\n", *root, *addr) //nolint:errcheck
+
+	server := &http.Server{Addr: *addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+
+	return server.ListenAndServe()
+}
+
+// A server renders the template for each request, so that changes are visible
+// when the page is reloaded.
+//
+// A --data flag that names a directory, instead of a file, names a directory of
+// per-route data files: a request for /blog/post binds the variables in
+// blog/post.json, blog/post.yaml or blog/post.yml in that directory, if one exists.
+// A request for a directory uses its index file. Query parameters are bound last;
+// a parameter such as page.title=Home sets a nested variable.
+type server struct {
+	root     string
+	dataDirs []string
+	engine   *liquid.Engine
+}
+
+func newServer(root string) *server {
+	s := &server{root: root, engine: newEngine()}
+
+	// Separate the data directories from the data files.
+	sources := []bindingSource{}
+
+	for _, src := range bindingSources {
+		if fi, err := os.Stat(src.value); src.flag == "data" && err == nil && fi.IsDir() {
+			s.dataDirs = append(s.dataDirs, src.value)
+			continue
+		}
+
+		sources = append(sources, src)
+	}
+
+	bindingSources = sources
+
+	// Includes can be named relative to the root, from templates in subdirectories.
+	s.engine.SetIncludePaths(append(append([]string{}, includePaths...), root)...)
+
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	route := path.Clean("/" + r.URL.Path)
+
+	filename, ok := s.resolve(route)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !isTemplate(filename) {
+		http.ServeFile(w, r, filename)
+		return
+	}
+
+	out, err := s.render(filename, route, r)
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		writeErrorOverlay(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType(filename))
+	w.Write(out) //nolint:errcheck
+}
+
+// resolve returns the file that serves route.
+func (s *server) resolve(route string) (string, bool) {
+	name := filepath.Join(s.root, filepath.FromSlash(route))
+
+	candidates := []string{name, name + ".html", name + ".liquid"}
+	if fi, err := os.Stat(name); err == nil && fi.IsDir() {
+		candidates = []string{filepath.Join(name, "index.html"), filepath.Join(name, "index.liquid")}
+	}
+
+	for _, c := range candidates {
+		if fi, err := os.Stat(c); err == nil && !fi.IsDir() {
+			return c, true
+		}
+	}
+
+	return "", false
+}
+
+// render renders the template in filename, with the bindings for the request.
+func (s *server) render(filename, route string, r *http.Request) ([]byte, error) {
+	vars := map[string]any{}
+	for k, v := range bindings {
+		vars[k] = v
+	}
+
+	// Re-read the data files, in case they have changed.
+	if err := applyBindingFlags(vars, ""); err != nil {
+		return nil, err
+	}
+
+	if data, err := s.routeData(route); err != nil {
+		return nil, err
+	} else if data != nil {
+		deepMerge(vars, data)
+	}
+
+	for key, values := range r.URL.Query() {
+		var v any = values
+		if len(values) == 1 {
+			v = values[0]
+		}
+
+		deepMerge(vars, nestedBinding(strings.Split(key, "."), v))
+	}
+
+	source, err := os.ReadFile(filename) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := s.engine.ParseTemplateLocation(source, filename, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return tpl.Render(vars)
+}
+
+// routeData reads the data file for route from the data directories, if there is one.
+func (s *server) routeData(route string) (map[string]any, error) {
+	name := strings.TrimSuffix(route, path.Ext(route))
+	if name == "/" || strings.HasSuffix(name, "/") {
+		name += "index"
+	}
+
+	var data map[string]any
+
+	for _, dir := range s.dataDirs {
+		for _, ext := range []string{".json", ".yaml", ".yml"} {
+			filename := filepath.Join(dir, filepath.FromSlash(name)+ext)
+			if _, err := os.Stat(filename); err != nil {
+				continue
+			}
+
+			v, err := readDataFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			if data == nil {
+				data = map[string]any{}
+			}
+
+			deepMerge(data, v)
+		}
+	}
+
+	return data, nil
+}
+
+// isTemplate returns true if filename is rendered, instead of served as is.
+func isTemplate(filename string) bool {
+	if filepath.Ext(filename) == ".liquid" {
+		return true
+	}
+
+	t := mime.TypeByExtension(filepath.Ext(filename))
+
+	return strings.HasPrefix(t, "text/") || strings.Contains(t, "json") || strings.Contains(t, "xml") || strings.Contains(t, "javascript")
+}
+
+// contentType returns the content type of the output of the template in filename.
+// The type of page.xml.liquid is that of page.xml; other .liquid files are HTML.
+func contentType(filename string) string {
+	if t := mime.TypeByExtension(filepath.Ext(strings.TrimSuffix(filename, ".liquid"))); t != "" {
+		return t
+	}
+
+	return "text/html; charset=utf-8"
+}
+
+// writeErrorOverlay writes an HTML page that shows err, with the source around its location.
+func writeErrorOverlay(w http.ResponseWriter, err error) {
+	fmt.Fprint(w, `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Liquid error</title><style>
+.liquid-error { position: fixed; inset: 0; overflow: auto; padding: 2em; background: rgba(0, 0, 0, 0.85); color: #eee; font: 14px/1.5 monospace; }
+.liquid-error h1 { color: #ff6b6b; font-size: 1.2em; white-space: pre-wrap; }
+.liquid-error pre { background: #222; padding: 1em; }
+.liquid-error .line { display: block; }
+.liquid-error .current { background: #633; color: #fff; }
+</style></head>
+<body><div class="liquid-error">
+`) //nolint:errcheck
+	fmt.Fprintf(w, "<h1>%s</h1>\n", html.EscapeString(err.Error())) //nolint:errcheck
+
+	var se liquid.SourceError
+	if errors.As(err, &se) && se.Path() != "" && se.LineNumber() > 0 {
+		if source, err := os.ReadFile(se.Path()); err == nil {
+			writeSourceSnippet(w, se.Path(), strings.Split(string(source), "\n"), se.LineNumber())
+		}
+	}
+
+	fmt.Fprint(w, "</div></body></html>\n") //nolint:errcheck
+}
+
+// writeSourceSnippet writes the lines around line, with that line highlighted.
+func writeSourceSnippet(w http.ResponseWriter, filename string, lines []string, line int) {
+	const context = 3
+
+	fmt.Fprintf(w, "<p>%s:%d</p>\n<pre>", html.EscapeString(filename), line) //nolint:errcheck
+
+	for i := max(line-context, 1); i <= min(line+context, len(lines)); i++ {
+		class := "line"
+		if i == line {
+			class += " current"
+		}
+
+		fmt.Fprintf(w, `<span class="%s">%4d  %s</span>`, class, i, html.EscapeString(lines[i-1])) //nolint:errcheck
+	}
+
+	fmt.Fprint(w, "</pre>\n") //nolint:errcheck
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	defer func() { bindings = map[string]any{} }()
+
+	flags := flag.NewFlagSet("liquid serve", flag.ContinueOnError)
+	addBindingFlags(flags)
+	require.NoError(t, flags.Parse([]string{"--data", "testdata/serve/site.yaml", "--data", "testdata/serve/data"}))
+
+	ts := httptest.NewServer(newServer("testdata/serve/templates"))
+	defer ts.Close()
+
+	get := func(path string) (int, string, string) {
+		resp, err := http.Get(ts.URL + path) // #nosec G107
+		require.NoError(t, err)
+
+		defer resp.Body.Close() //nolint:errcheck
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	status, ctype, body := get("/")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, ctype, "text/html")
+	require.Equal(t, "<nav>Preview</nav><h1>Home</h1>", body)
+
+	// query parameters
+	_, _, body = get("/?q=search&title=Query")
+	require.Equal(t, "<nav>Preview</nav><h1>Query</h1>search", body)
+
+	// per-route data, and a route without an extension
+	_, _, body = get("/blog/post")
+	require.Equal(t, "<nav>Preview</nav><h2>Hello by Ann</h2>", body)
+
+	_, _, body = get("/blog/post.html?post.author=Bo")
+	require.Equal(t, "<nav>Preview</nav><h2>Hello by Bo</h2>", body)
+
+	// static files
+	status, ctype, body = get("/style.css")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, ctype, "text/css")
+	require.Contains(t, body, "color: red")
+
+	status, _, _ = get("/missing.html")
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, _, _ = get("/../serve_test.go")
+	require.Equal(t, http.StatusNotFound, status)
+
+	// errors
+	status, ctype, body = get("/broken.html")
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Contains(t, ctype, "text/html")
+	require.Contains(t, body, "undefined filter")
+	require.Contains(t, body, `<span class="line current">   3  {{ x | nofilter }}</span>`)
+	require.Contains(t, body, "line 1")
+}
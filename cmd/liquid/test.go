@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/osteele/liquid/liquidtest"
+)
+
+// test runs the golden-file test cases in directory trees; see package liquidtest.
+func test(flags *flag.FlagSet, args []string) error {
+	update := flags.Bool("update", false, "replace the expected output files with the actual output")
+	verbose := flags.Bool("v", false, "list the test cases that pass")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	vars := map[string]any{}
+	for k, v := range bindings {
+		vars[k] = v
+	}
+
+	if err := applyBindingFlags(vars, ""); err != nil {
+		return err
+	}
+
+	opts := liquidtest.Options{Engine: newEngine(), Bindings: vars, Update: *update}
+	total, failed := 0, 0
+
+	for _, dir := range dirs {
+		results, err := liquidtest.Run(dir, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range results {
+			total++
+
+			switch {
+			case !r.Passed():
+				failed++
+
+				fmt.Fprintf(stdout, "--- FAIL: %s\n", r.Template) //nolint:errcheck
+
+				if r.Err != nil {
+					fmt.Fprintf(stdout, "    %s\n", r.Err) //nolint:errcheck
+				} else {
+					fmt.Fprint(stdout, indent(r.Diff, "    ")) //nolint:errcheck
+				}
+			case r.Updated:
+				fmt.Fprintf(stdout, "updated %s\n", r.Expected) //nolint:errcheck
+			case *verbose:
+				fmt.Fprintf(stdout, "--- PASS: %s\n", r.Template) //nolint:errcheck
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("FAIL: %d of %d test cases failed", failed, total)
+	}
+
+	fmt.Fprintf(stdout, "ok: %d test cases\n", total) //nolint:errcheck
+
+	return nil
+}
+
+// indent prefixes each line of s.
+func indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand(t *testing.T) {
+	oldArgs := os.Args
+
+	defer func() {
+		os.Args = oldArgs
+		stderr = os.Stderr
+		stdout = os.Stdout
+		exit = os.Exit
+		bindings = map[string]any{}
+	}()
+
+	exitCode := -1
+	exit = func(n int) { exitCode = n }
+
+	buf := &bytes.Buffer{}
+	stdout, stderr = buf, buf
+	os.Args = []string{"liquid", "test", "-v", "--var", "name=World", "testdata/golden"}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Contains(t, buf.String(), "--- PASS: testdata/golden/greet.liquid")
+	require.Contains(t, buf.String(), "ok: 1 test cases")
+
+	// failure, with a diff
+	buf.Reset()
+	os.Args = []string{"liquid", "test", "--var", "name=Go", "testdata/golden"}
+
+	main()
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, buf.String(), "--- FAIL: testdata/golden/greet.liquid")
+	require.Contains(t, buf.String(), "    -Hello, World!\n    +Hello, Go!\n")
+	require.Contains(t, buf.String(), "FAIL: 1 of 1 test cases failed")
+
+	// update
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.liquid"), []byte("{{ 1 | plus: 1 }}"), 0o600))
+
+	exitCode = -1
+	buf.Reset()
+	os.Args = []string{"liquid", "test", "-update", dir}
+
+	main()
+	require.Equal(t, -1, exitCode)
+	require.Contains(t, buf.String(), "updated "+filepath.Join(dir, "a.expected"))
+
+	b, err := os.ReadFile(filepath.Join(dir, "a.expected")) // #nosec G304
+	require.NoError(t, err)
+	require.Equal(t, "2", string(b))
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// watch renders a directory tree like build, and then polls the templates, the
+// templates that they include, and the data files, and re-renders the outputs
+// that changes affect. It runs until it is interrupted.
+func watch(flags *flag.FlagSet, args []string) error {
+	b := addBuildFlags(flags)
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often to check for changes")
+
+	if err := b.parse(flags, args); err != nil {
+		return err
+	}
+
+	w := newWatcher(b)
+	for {
+		w.poll()
+		time.Sleep(*interval)
+	}
+}
+
+// A watcher re-renders the outputs of a builder whose sources have changed.
+//
+// It polls modification times and sizes, instead of using a platform-specific
+// file notification API.
+type watcher struct {
+	*builder
+
+	store  *recordingTemplateStore
+	stamps map[string]fileStamp // the files that have been checked, as of the last check
+	// the files that the rendering of each template read, by the template's path
+	// relative to the source directory
+	deps map[string][]string
+}
+
+// A fileStamp identifies a version of a file.
+type fileStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func statFile(name string) fileStamp {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{true, fi.ModTime(), fi.Size()}
+}
+
+func newWatcher(b *builder) *watcher {
+	store := &recordingTemplateStore{}
+	b.engine.RegisterTemplateStore(store)
+
+	return &watcher{
+		builder: b,
+		store:   store,
+		stamps:  map[string]fileStamp{},
+		deps:    map[string][]string{},
+	}
+}
+
+// poll renders the templates that are new, or that have changed, and removes
+// the outputs of templates that have been deleted. It prints errors instead of
+// returning them, so that they don't stop the watch.
+func (w *watcher) poll() {
+	changed := w.changedFiles()
+
+	dataChanged := false
+
+	for name := range changed {
+		if isDataFile(name) {
+			dataChanged = true
+		}
+	}
+
+	if dataChanged && len(w.deps) > 0 {
+		if err := w.loadBindings(); err != nil {
+			fmt.Fprintln(stderr, err) //nolint:errcheck
+			return
+		}
+	}
+
+	files, err := w.files()
+	if err != nil {
+		fmt.Fprintln(stderr, err) //nolint:errcheck
+		return
+	}
+
+	current := map[string]bool{}
+
+	for _, rel := range files {
+		current[rel] = true
+
+		deps, ok := w.deps[rel]
+		if ok && !dataChanged && !anyChanged(deps, changed) {
+			continue
+		}
+
+		w.renderRecordingDeps(rel)
+	}
+
+	for rel := range w.deps {
+		if !current[rel] {
+			delete(w.deps, rel)
+
+			dst := filepath.Join(w.outDir, rel)
+			if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintln(stderr, err) //nolint:errcheck
+			} else {
+				fmt.Fprintf(stderr, "removed %s\n", dst) //nolint:errcheck
+			}
+		}
+	}
+}
+
+// changedFiles checks the files that are already known, and the data files,
+// and returns the set of those that have changed since the last check.
+func (w *watcher) changedFiles() map[string]bool {
+	changed := map[string]bool{}
+
+	check := func(name string) {
+		stamp := statFile(name)
+		if old, ok := w.stamps[name]; !ok || old != stamp {
+			changed[name] = true
+		}
+
+		w.stamps[name] = stamp
+	}
+
+	for name := range w.stamps {
+		check(name)
+	}
+
+	for _, s := range bindingSources {
+		switch {
+		case s.flag == "data" && s.value != "-":
+			check(s.value)
+		case s.flag == "data-dir":
+			_, dir := splitDataDir(s.value)
+			_ = filepath.WalkDir(dir, func(name string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					check(name)
+				}
+
+				return nil
+			})
+		}
+	}
+
+	return changed
+}
+
+// isDataFile returns true if name is a --data file, or is in a --data-dir directory.
+func isDataFile(name string) bool {
+	for _, s := range bindingSources {
+		switch s.flag {
+		case "data":
+			if name == s.value {
+				return true
+			}
+		case "data-dir":
+			_, dir := splitDataDir(s.value)
+			if rel, err := filepath.Rel(dir, name); err == nil && filepath.IsLocal(rel) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// renderRecordingDeps renders the template at rel, and records the files that it read.
+func (w *watcher) renderRecordingDeps(rel string) {
+	w.store.reset()
+	err := w.render(rel)
+
+	deps := append([]string{filepath.Join(w.srcDir, rel)}, w.store.filenames()...)
+	for _, name := range deps {
+		if _, ok := w.stamps[name]; !ok {
+			w.stamps[name] = statFile(name)
+		}
+	}
+
+	w.deps[rel] = deps
+
+	if err != nil {
+		fmt.Fprintln(stderr, err) //nolint:errcheck
+		return
+	}
+
+	fmt.Fprintf(stderr, "rendered %s\n", filepath.Join(w.outDir, rel)) //nolint:errcheck
+}
+
+func anyChanged(names []string, changed map[string]bool) bool {
+	for _, name := range names {
+		if changed[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A recordingTemplateStore is a render.TemplateStore that records the names of the
+// files that it is asked for, including those that don't exist, so that a watcher
+// notices when they are created.
+type recordingTemplateStore struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+func (s *recordingTemplateStore) ReadTemplate(filename string) ([]byte, error) {
+	s.mu.Lock()
+	if s.names == nil {
+		s.names = map[string]bool{}
+	}
+
+	s.names[filename] = true
+	s.mu.Unlock()
+
+	return os.ReadFile(filename) // #nosec G304
+}
+
+func (s *recordingTemplateStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.names = nil
+}
+
+// filenames returns the names that have been read since the last reset, in sorted order.
+func (s *recordingTemplateStore) filenames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.names))
+	for name := range s.names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	defer func() {
+		stderr = os.Stderr
+		bindings = map[string]any{}
+	}()
+
+	var (
+		dir     = t.TempDir()
+		src     = filepath.Join(dir, "src")
+		out     = filepath.Join(dir, "out")
+		mtime   = time.Now()
+		buf     = &bytes.Buffer{}
+		flags   = newFlagSet("liquid watch", commands["watch"])
+		b       = addBuildFlags(flags)
+		include = filepath.Join(src, "_includes")
+	)
+
+	stderr = buf
+
+	// write writes a file, with a later modification time than the previous one
+	write := func(name, content string) {
+		name = filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o700))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+
+		mtime = mtime.Add(time.Second)
+		require.NoError(t, os.Chtimes(name, mtime, mtime))
+	}
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(out, name)) // #nosec G304
+		require.NoError(t, err)
+
+		return string(b)
+	}
+
+	// poll polls, and returns what it printed
+	poll := func(w *watcher) string {
+		buf.Reset()
+		w.poll()
+
+		return buf.String()
+	}
+
+	write("src/index.html", `{% include "nav.html" %} {{ site.title }}{{ data.footer.text }}`)
+	write("src/about.html", `About {{ site.title }}`)
+	write("src/_includes/nav.html", `nav`)
+	write("site.yaml", "site:\n  title: Site\n")
+	write("_data/footer.yml", "text: \"\"\n")
+
+	require.NoError(t, b.parse(flags, []string{src, "-o", out, "--includes", include, "--data", filepath.Join(dir, "site.yaml"), "--data-dir", filepath.Join(dir, "_data")}))
+	w := newWatcher(b)
+
+	log := poll(w)
+	require.Contains(t, log, "index.html")
+	require.Contains(t, log, "about.html")
+	require.Equal(t, "nav Site", read("index.html"))
+	require.Equal(t, "About Site", read("about.html"))
+
+	// nothing has changed
+	require.Empty(t, poll(w))
+
+	// an include changes
+	write("src/_includes/nav.html", `NAV`)
+
+	log = poll(w)
+	require.Contains(t, log, "index.html")
+	require.NotContains(t, log, "about.html")
+	require.Equal(t, "NAV Site", read("index.html"))
+
+	// a template changes
+	write("src/about.html", `About us`)
+
+	log = poll(w)
+	require.NotContains(t, log, "index.html")
+	require.Contains(t, log, "about.html")
+
+	// the data changes
+	write("site.yaml", "site:\n  title: New\n")
+
+	log = poll(w)
+	require.Contains(t, log, "index.html")
+	require.Contains(t, log, "about.html")
+	require.Equal(t, "NAV New", read("index.html"))
+
+	// a file in the data directory changes
+	write("_data/footer.yml", "text: \" footer\"\n")
+
+	log = poll(w)
+	require.Contains(t, log, "index.html")
+	require.Equal(t, "NAV New footer", read("index.html"))
+
+	// an error is printed, and the next change is rendered
+	write("src/index.html", `{{ x | nofilter }}`)
+
+	log = poll(w)
+	require.Contains(t, log, "nofilter")
+	require.Equal(t, "NAV New footer", read("index.html"))
+
+	write("src/index.html", `fixed`)
+	require.Contains(t, poll(w), "index.html")
+	require.Equal(t, "fixed", read("index.html"))
+
+	// a template is added, and one is removed
+	write("src/new.html", `new`)
+	require.NoError(t, os.Remove(filepath.Join(src, "about.html")))
+
+	log = poll(w)
+	require.Contains(t, log, "rendered "+filepath.Join(out, "new.html"))
+	require.Contains(t, log, "removed "+filepath.Join(out, "about.html"))
+	require.NoFileExists(t, filepath.Join(out, "about.html"))
+}
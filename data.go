@@ -0,0 +1,270 @@
+package liquid
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A DataDecoder decodes the contents of a data file into a template value.
+type DataDecoder func(data []byte) (any, error)
+
+// A DataLoader builds bindings from a directory of data files, as Jekyll builds
+// site.data from its _data directory.
+type DataLoader struct {
+	// Decoders maps file extensions, such as ".toml", to the decoders for
+	// files with those extensions. They take precedence over the built-in
+	// decoders, for .yml, .yaml, .json, .csv and .tsv files.
+	Decoders map[string]DataDecoder
+}
+
+// LoadData builds bindings from the data files in the directory dir of fsys,
+// with the built-in decoders. See DataLoader.Load.
+func LoadData(fsys fs.FS, dir string) (map[string]any, error) {
+	return DataLoader{}.Load(fsys, dir)
+}
+
+// Load builds bindings from the data files in the directory dir of fsys. Each
+// file is bound to its name without its extension, and each subdirectory to the
+// bindings of its files, so that the data in members.yml and authors/jane.json
+// is accessed as members and authors.jane.
+//
+// YAML mappings are yaml.MapSlice values, so that a {% for %} loop iterates over
+// them in the order of the file. CSV and TSV files are arrays of rows; each row
+// is a map from the column names in the first line to the row's values, as strings.
+// Files whose extensions don't have a decoder, and names that begin with ".", are
+// skipped. It is an error for two files, or a file and a directory, to have the
+// same name.
+func (l DataLoader) Load(fsys fs.FS, dir string) (map[string]any, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		filename := path.Join(dir, name)
+
+		var (
+			key   string
+			value any
+		)
+
+		if entry.IsDir() {
+			key = name
+			if value, err = l.Load(fsys, filename); err != nil {
+				return nil, err
+			}
+		} else {
+			ext := path.Ext(name)
+
+			decode := l.decoder(ext)
+			if decode == nil {
+				continue
+			}
+
+			source, err := fs.ReadFile(fsys, filename)
+			if err != nil {
+				return nil, err
+			}
+
+			key = strings.TrimSuffix(name, ext)
+			if value, err = decode(source); err != nil {
+				return nil, fmt.Errorf("%s: %w", filename, err)
+			}
+		}
+
+		if _, ok := data[key]; ok {
+			return nil, fmt.Errorf("%s: more than one data file or directory is named %q", dir, key)
+		}
+
+		data[key] = value
+	}
+
+	return data, nil
+}
+
+// LoadDataBindings reads variable bindings from the data file name in fsys, with
+// the built-in decoders. See DataLoader.LoadBindings.
+func LoadDataBindings(fsys fs.FS, name string) (map[string]any, error) {
+	return DataLoader{}.LoadBindings(fsys, name)
+}
+
+// LoadBindings reads variable bindings from the data file name in fsys, such as
+// the data file of a test case. The file is decoded as Load decodes it, and its
+// top level must be a mapping. It is an error if its extension doesn't have a
+// decoder.
+func (l DataLoader) LoadBindings(fsys fs.FS, name string) (map[string]any, error) {
+	decode := l.decoder(path.Ext(name))
+	if decode == nil {
+		return nil, fmt.Errorf("%s: no decoder for %q files", name, path.Ext(name))
+	}
+
+	source, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := decode(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	bindings, err := bindingsOf(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return bindings, nil
+}
+
+func (l DataLoader) decoder(ext string) DataDecoder {
+	if decode, ok := l.Decoders[ext]; ok {
+		return decode
+	}
+
+	switch strings.ToLower(ext) {
+	case ".yml", ".yaml":
+		return DecodeYAML
+	case ".json":
+		return decodeJSONData
+	case ".csv":
+		return decodeDelimitedData(',')
+	case ".tsv":
+		return decodeDelimitedData('\t')
+	default:
+		return nil
+	}
+}
+
+// DecodeYAML decodes YAML data, as front matter and data files are decoded. Its
+// mappings are yaml.MapSlice values, so that a {% for %} loop iterates over them
+// in the order of the source.
+func DecodeYAML(data []byte) (any, error) {
+	var v orderedYAML
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return v.value, nil
+}
+
+// DecodeYAMLBindings decodes YAML data whose top level is a mapping, such as front
+// matter, as variable bindings. The values are decoded as DecodeYAML decodes them.
+// Empty data decodes as empty bindings.
+func DecodeYAMLBindings(data []byte) (map[string]any, error) {
+	v, err := DecodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return bindingsOf(v)
+}
+
+// bindingsOf returns the variable bindings of a decoded mapping.
+func bindingsOf(v any) (map[string]any, error) {
+	switch v := v.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case yaml.MapSlice:
+		bindings := make(map[string]any, len(v))
+		for _, item := range v {
+			bindings[fmt.Sprint(item.Key)] = item.Value
+		}
+
+		return bindings, nil
+	case map[string]any:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("expected a mapping, found %T", v)
+	}
+}
+
+// orderedYAML decodes YAML mappings, including those in a top-level sequence, as
+// yaml.MapSlice values.
+type orderedYAML struct{ value any }
+
+func (o *orderedYAML) UnmarshalYAML(unmarshal func(any) error) error {
+	if err := unmarshal(&o.value); err != nil {
+		return err
+	}
+
+	switch o.value.(type) {
+	case map[any]any:
+		var m yaml.MapSlice
+		if err := unmarshal(&m); err != nil {
+			return err
+		}
+
+		o.value = m
+	case []any:
+		var items []orderedYAML
+		if err := unmarshal(&items); err != nil {
+			return err
+		}
+
+		seq := make([]any, len(items))
+		for i, item := range items {
+			seq[i] = item.value
+		}
+
+		o.value = seq
+	}
+
+	return nil
+}
+
+func decodeJSONData(data []byte) (any, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func decodeDelimitedData(delim rune) DataDecoder {
+	return func(data []byte) (any, error) {
+		r := csv.NewReader(bytes.NewReader(data))
+		r.Comma = delim
+		r.LazyQuotes = delim == '\t'
+
+		header, err := r.Read()
+		if err == io.EOF {
+			return []any{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		rows := []any{}
+
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return rows, nil
+			} else if err != nil {
+				return nil, err
+			}
+
+			row := make(map[string]any, len(header))
+			for i, name := range header {
+				row[name] = record[i]
+			}
+
+			rows = append(rows, row)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package liquid
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestLoadData(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_data/members.yml":       {Data: []byte("- name: Ann\n  role: editor\n- name: Bo\n  role: author\n")},
+		"_data/nav.yaml":          {Data: []byte("home: /\nabout: /about/\nblog: /blog/\n")},
+		"_data/settings.json":     {Data: []byte(`{"title": "Site", "tags": ["a", "b"]}`)},
+		"_data/prices.csv":        {Data: []byte("sku,price\nH1,10\n\"C,2\",5\n")},
+		"_data/stock.tsv":         {Data: []byte("sku\tcount\nH1\t3\n")},
+		"_data/authors/jane.json": {Data: []byte(`{"name": "Jane"}`)},
+		"_data/notes.txt":         {Data: []byte("skipped")},
+		"_data/.hidden.yml":       {Data: []byte("skipped: true")},
+	}
+
+	data, err := LoadData(fsys, "_data")
+	require.NoError(t, err)
+	require.Equal(t, []string{"authors", "members", "nav", "prices", "settings", "stock"}, sortedKeys(data))
+	require.Equal(t, yaml.MapSlice{{Key: "home", Value: "/"}, {Key: "about", Value: "/about/"}, {Key: "blog", Value: "/blog/"}}, data["nav"])
+	require.Equal(t, []any{map[string]any{"sku": "H1", "price": "10"}, map[string]any{"sku": "C,2", "price": "5"}}, data["prices"])
+
+	engine := NewEngine()
+	out, err := engine.ParseAndRenderString(
+		`{% for m in site.data.members %}{{ m.name }}:{{ m.role }} {% endfor %}`+
+			`{% for link in site.data.nav %}{{ link[0] }} {% endfor %}`+
+			`{{ site.data.settings.tags | join: "," }} {{ site.data.stock[0].count }} {{ site.data.authors.jane.name }}`,
+		Bindings{"site": map[string]any{"data": data}})
+	require.NoError(t, err)
+	require.Equal(t, "Ann:editor Bo:author home about blog a,b 3 Jane", out)
+
+	// custom formats, and errors
+	loader := DataLoader{Decoders: map[string]DataDecoder{
+		".txt": func(b []byte) (any, error) { return strings.ToUpper(string(b)), nil },
+	}}
+	data, err = loader.Load(fsys, "_data")
+	require.NoError(t, err)
+	require.Equal(t, "SKIPPED", data["notes"])
+
+	_, err = LoadData(fstest.MapFS{"d/a.yml": {Data: []byte("x: 1")}, "d/a.json": {Data: []byte("{}")}}, "d")
+	require.ErrorContains(t, err, `more than one data file or directory is named "a"`)
+	_, err = LoadData(fstest.MapFS{"d/a.json": {Data: []byte("{")}}, "d")
+	require.ErrorContains(t, err, "d/a.json")
+	_, err = LoadData(fsys, "missing")
+	require.Error(t, err)
+}
+
+func TestLoadDataBindings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.yaml":  {Data: []byte("title: About\nnav:\n  z: 1\n  a: 2\n")},
+		"page.json":  {Data: []byte(`{"title": "About"}`)},
+		"list.json":  {Data: []byte(`[1]`)},
+		"notes.txt":  {Data: []byte("notes")},
+		"broken.yml": {Data: []byte("a: [")},
+	}
+
+	b, err := LoadDataBindings(fsys, "page.yaml")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"title": "About", "nav": yaml.MapSlice{{Key: "z", Value: 1}, {Key: "a", Value: 2}}}, b)
+
+	b, err = LoadDataBindings(fsys, "page.json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"title": "About"}, b)
+
+	_, err = LoadDataBindings(fsys, "list.json")
+	require.ErrorContains(t, err, "list.json: expected a mapping")
+	_, err = LoadDataBindings(fsys, "notes.txt")
+	require.ErrorContains(t, err, "no decoder")
+	_, err = LoadDataBindings(fsys, "broken.yml")
+	require.ErrorContains(t, err, "broken.yml")
+	_, err = LoadDataBindings(fsys, "missing.yml")
+	require.Error(t, err)
+}
+
+func TestDecodeYAMLBindings(t *testing.T) {
+	b, err := DecodeYAMLBindings([]byte("title: About\nnav:\n  z: 1\n  a: 2\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"title": "About", "nav": yaml.MapSlice{{Key: "z", Value: 1}, {Key: "a", Value: 2}}}, b)
+
+	b, err = DecodeYAMLBindings(nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{}, b)
+
+	_, err = DecodeYAMLBindings([]byte("- a\n"))
+	require.ErrorContains(t, err, "expected a mapping")
+	_, err = DecodeYAMLBindings([]byte("a: ["))
+	require.Error(t, err)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -1,5 +1,7 @@
 package liquid
 
+import "github.com/osteele/liquid/render"
+
 // Drop indicates that the object will present to templates as its ToLiquid value.
 type Drop interface {
 	ToLiquid() any
@@ -15,3 +17,24 @@ func FromDrop(object any) any {
 		return object
 	}
 }
+
+// A ContextDrop is like a Drop, except that its value can depend on the render, for
+// example on the current locale or shop. When a template reads a variable,
+// property, element or filter result that is a ContextDrop, it uses the value of
+// its ToLiquidContext method, called with the context of the tag or object that
+// reads it.
+//
+// The context's Get method returns the values of the render's variables.
+// ToLiquidContext may be called more than once per render, so it should cache
+// expensive results.
+type ContextDrop interface {
+	ToLiquidContext(render.Context) any
+}
+
+// A MethodMissing provides the properties that a value doesn't otherwise have,
+// as in Ruby's liquid_method_missing. LiquidMethodMissing is called with the
+// name of a property that is missing or nil, when a template reads it. This lets
+// a large object compute its properties only when they are used.
+type MethodMissing interface {
+	LiquidMethodMissing(name string) any
+}
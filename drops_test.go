@@ -3,9 +3,12 @@ package liquid
 import (
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/osteele/liquid/render"
 )
 
 type dropTest struct{}
@@ -93,3 +96,78 @@ func ExampleDrop_struct() {
 	fmt.Println(out)
 	// Output: blue AWD Model S85
 }
+
+// A greeting is a drop whose value depends on the render's locale variable.
+type greeting struct{}
+
+func (g greeting) ToLiquidContext(ctx render.Context) any {
+	if ctx.Get("locale") == "fr" {
+		return "Bonjour"
+	}
+
+	return "Hello"
+}
+
+// A locator is a drop whose value describes the tag or object that reads it.
+type locator struct{}
+
+func (locator) ToLiquidContext(ctx render.Context) any {
+	return fmt.Sprintf("[%s|%s|%s]", ctx.TagName(), ctx.SourceFile(), ctx.Errorf("here").Error())
+}
+
+// A catalog computes its products when they are read.
+type catalog struct{ lookups *[]string }
+
+func (c catalog) LiquidMethodMissing(name string) any {
+	*c.lookups = append(*c.lookups, name)
+	return map[string]any{"title": strings.ToUpper(name), "greeting": greeting{}}
+}
+
+func TestContextDrops(t *testing.T) {
+	engine := NewEngine()
+
+	var lookups []string
+
+	bindings := map[string]any{
+		"greeting": greeting{},
+		"shop":     map[string]any{"greeting": greeting{}, "greetings": []any{greeting{}}},
+		"catalog":  catalog{&lookups},
+	}
+
+	tests := []struct{ in, expected string }{
+		{`{{ greeting }}`, "Hello"},
+		{`{% assign locale = "fr" %}{{ greeting }} {{ shop.greeting }} {{ shop["greeting"] }}`, "Bonjour Bonjour Bonjour"},
+		{`{% assign locale = "fr" %}{{ shop.greetings.first }} {{ shop.greetings | first }}`, "Bonjour Bonjour"},
+		{`{% for g in shop.greetings %}{{ g }} {% assign locale = "fr" %}{{ g }}{% endfor %}`, "Hello Bonjour"},
+		{`{{ catalog.hat.title }} {{ catalog["shoe"].title }}`, "HAT SHOE"},
+		{`{% assign locale = "fr" %}{{ catalog.hat.greeting }}`, "Bonjour"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := engine.ParseAndRenderString(test.in, bindings)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out)
+		})
+	}
+
+	require.Equal(t, []string{"hat", "shoe", "hat"}, lookups)
+}
+
+func TestContextDrops_location(t *testing.T) {
+	engine := NewEngine()
+	bindings := map[string]any{"loc": locator{}}
+
+	render := func(src string) string {
+		tpl, err := engine.ParseTemplateLocation([]byte(src), "page.html", 1)
+		require.NoError(t, err)
+		out, err := tpl.RenderString(bindings)
+		require.NoError(t, err)
+
+		return out
+	}
+
+	require.Equal(t, "\n[|page.html|Liquid error (line 2): here in page.html]", render("\n{{ loc }}"))
+	require.Equal(t, "\n\n[assign|page.html|Liquid error (line 3): here in page.html]",
+		render("\n\n{% if loc %}{% assign x = loc %}{{ x }}{% endif %}"))
+}
@@ -2,20 +2,29 @@ package liquid
 
 import (
 	"io"
+	"time"
 
+	"github.com/osteele/liquid/expressions"
 	"github.com/osteele/liquid/filters"
+	"github.com/osteele/liquid/parser"
 	"github.com/osteele/liquid/render"
 	"github.com/osteele/liquid/tags"
+	"github.com/osteele/liquid/values"
 )
 
 // An Engine parses template source into renderable text.
 //
 // An engine can be configured with additional filters and tags.
-type Engine struct{ cfg render.Config }
+type Engine struct {
+	cfg render.Config
+
+	frontMatter         bool
+	frontMatterVariable string
+}
 
 // NewEngine returns a new Engine.
 func NewEngine() *Engine {
-	e := Engine{render.NewConfig()}
+	e := Engine{cfg: render.NewConfig(), frontMatterVariable: "page"}
 	filters.AddStandardFilters(&e.cfg)
 	filters.AddExtensionFilters(&e.cfg)
 	tags.AddStandardTags(&e.cfg)
@@ -25,7 +34,7 @@ func NewEngine() *Engine {
 
 // NewBasicEngine returns a new Engine without the standard filters or tags.
 func NewBasicEngine() *Engine {
-	return &Engine{render.NewConfig()}
+	return &Engine{cfg: render.NewConfig(), frontMatterVariable: "page"}
 }
 
 // RegisterBlock defines a block e.g. {% tag %}…{% endtag %}.
@@ -45,7 +54,9 @@ func (e *Engine) RegisterBlock(name string, td Renderer) {
 // RegisterFilter defines a Liquid filter, for use as `{{ value | my_filter }}` or `{{ value | my_filter: arg }}`.
 //
 // A filter is a function that takes at least one input, and returns one or two outputs.
-// If it returns two outputs, the second must have type error.
+// If it returns two outputs, the second must have type error. A filter whose first
+// parameter is an expressions.FilterContext is called with the engine's configuration,
+// before its input; for example, to look up struct properties as SetFieldNamer names them.
 //
 // Examples:
 //
@@ -80,6 +91,13 @@ func (e *Engine) RegisterTemplateStore(templateStore render.TemplateStore) {
 	e.cfg.TemplateStore = templateStore
 }
 
+// SetIncludePaths sets the directories that {% include %} searches, in order, for
+// templates that aren't found relative to the including template; for example,
+// a Jekyll site's _includes directory.
+func (e *Engine) SetIncludePaths(dirs ...string) {
+	e.cfg.IncludePaths = dirs
+}
+
 // StrictVariables causes the renderer to error when the template contains an undefined variable.
 func (e *Engine) StrictVariables() {
 	e.cfg.StrictVariables = true
@@ -92,9 +110,90 @@ func (e *Engine) EnableJekyllExtensions() {
 	e.cfg.JekyllExtensions = true
 }
 
+// EnableFrontMatter causes templates that begin with YAML front matter, as in Jekyll,
+// to have it removed from their source:
+//
+//	---
+//	title: About
+//	---
+//	<h1>{{ page.title }}</h1>
+//
+// Template.FrontMatter returns the front matter, decoded by DecodeYAMLBindings, so that
+// its nested mappings keep their order. When the template is rendered,
+// the front matter is bound to the "page" variable, or to the variable that is set by
+// SetFrontMatterVariable. Line numbers in errors are those in the original source.
+func (e *Engine) EnableFrontMatter() {
+	e.frontMatter = true
+}
+
+// SetFrontMatterVariable sets the name of the variable that front matter is bound to,
+// when EnableFrontMatter is in effect. The default is "page". If name is empty, the
+// front matter isn't bound.
+func (e *Engine) SetFrontMatterVariable(name string) {
+	e.frontMatterVariable = name
+}
+
+// SetFieldNamer sets the names by which templates access the fields and methods
+// of structs; for example, SnakeCaseFieldNames, so that a template accesses a
+// CompareAtPrice field as product.compare_at_price. A `liquid:"name"` field tag
+// takes precedence. The default is ExactFieldNames.
+//
+// The names of each struct type are computed once, and cached by the engine.
+func (e *Engine) SetFieldNamer(namer FieldNamer) {
+	e.cfg.StructNaming = values.NewStructNaming(namer)
+}
+
+// SetCharacterMode sets what templates count as a character of a string: for
+// the size, first and last properties, and for the size, slice, truncate and
+// capitalize filters. The default is RuneCharacters.
+func (e *Engine) SetCharacterMode(mode CharacterMode) {
+	e.cfg.CharacterMode = mode
+}
+
+// PromoteIntOverflow causes the plus, minus and times filters to return a *big.Int,
+// as Ruby does, when the result of an operation on two integers overflows an int64.
+// By default, such results wrap around.
+func (e *Engine) PromoteIntOverflow() {
+	e.cfg.PromoteIntOverflow = true
+}
+
+// SetTimeLocation sets the time zone of the date filter. Dates without a time zone,
+// such as "2024-03-01 09:00", are parsed in loc, and all dates, including times
+// with another time zone and Unix timestamps, are formatted in loc. The default
+// is to parse dates without a time zone in the local time zone, and to format
+// each time in its own time zone.
+//
+// A time zone argument to the filter takes precedence, as in
+// {{ order.created_at | date: "%H:%M", "Asia/Tokyo" }}.
+func (e *Engine) SetTimeLocation(loc *time.Location) {
+	e.cfg.TimeLocation = loc
+}
+
+// SetClock sets the function that returns the current time, for the "now" and
+// "today" inputs to the date filter. A fixed clock makes the output of templates
+// that use them deterministic, for example in tests. The default is time.Now.
+func (e *Engine) SetClock(now func() time.Time) {
+	e.cfg.Clock = now
+}
+
+// SetWarningHandler registers a function that is called with issues that don't cause
+// rendering to fail, but are probably mistakes; for example, {{ 1 < "one" }}, which is always false.
+// Each warning records the source location of the template node that caused it.
+func (e *Engine) SetWarningHandler(fn func(render.Warning)) {
+	e.cfg.WarningHandler = fn
+}
+
+// SetTracer registers a tracer that observes rendering: it is called when each template
+// node is entered and exited, when a filter is applied, when an included template is
+// loaded and compiled, and when a drop is resolved. Use it to collect metrics or build
+// tracing spans. Embed render.NopTracer to implement only some of its methods.
+func (e *Engine) SetTracer(t render.Tracer) {
+	e.cfg.Tracer = t
+}
+
 // ParseTemplate creates a new Template using the engine configuration.
 func (e *Engine) ParseTemplate(source []byte) (*Template, SourceError) {
-	return newTemplate(&e.cfg, source, "", 0)
+	return newTemplate(e, source, "", 0)
 }
 
 // ParseString creates a new Template using the engine configuration.
@@ -108,7 +207,42 @@ func (e *Engine) ParseString(source string) (*Template, SourceError) {
 // The path and line number are used for error reporting.
 // The path is also the reference for relative pathnames in the {% include %} tag.
 func (e *Engine) ParseTemplateLocation(source []byte, path string, line int) (*Template, SourceError) {
-	return newTemplate(&e.cfg, source, path, line)
+	return newTemplate(e, source, path, line)
+}
+
+// EvaluateString evaluates a Liquid expression, such as "product.price | times: 2",
+// with the engine's filters.
+func (e *Engine) EvaluateString(source string, b Bindings) (any, error) {
+	return expressions.EvaluateString(source, expressions.NewContext(b, e.cfg.Config.Config))
+}
+
+// ParseAST parses a template into an abstract syntax tree, without compiling it,
+// for tools that inspect templates. The path and line number are used for source
+// locations, as in ParseTemplateLocation. Front matter is removed, as in ParseTemplate.
+func (e *Engine) ParseAST(source []byte, path string, line int) (parser.ASTNode, SourceError) {
+	_, source, loc, err := e.removeFrontMatter(source, parser.SourceLoc{Pathname: path, LineNo: line})
+	if err != nil {
+		return nil, err
+	}
+
+	root, perr := e.cfg.Parse(string(source), loc)
+	if perr != nil {
+		return nil, perr
+	}
+
+	return root, nil
+}
+
+// ScanTokens returns the tokens of a template, as the parser sees them, for tools
+// that inspect templates. The path and line number are used for source locations,
+// as in ParseTemplateLocation. Front matter is removed, as in ParseTemplate.
+func (e *Engine) ScanTokens(source []byte, path string, line int) ([]parser.Token, SourceError) {
+	_, source, loc, err := e.removeFrontMatter(source, parser.SourceLoc{Pathname: path, LineNo: line})
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.Scan(string(source), loc, e.cfg.Delims), nil
 }
 
 // ParseAndRender parses and then renders the template.
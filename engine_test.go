@@ -5,12 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/osteele/liquid/parser"
+	"github.com/osteele/liquid/render"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
 	"io"
+	"math"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 var emptyBindings = map[string]any{}
@@ -285,3 +291,235 @@ func Test_ReadFile(t *testing.T) {
 	}
 	spew.Dump(result)
 }
+
+func TestEngine_PromoteIntOverflow(t *testing.T) {
+	bindings := Bindings{"max": int64(math.MaxInt64)}
+	src := `{{ max | plus: 1 }} {{ max | times: -2 }} {{ max | minus: 1 }} {{ max | plus: 0.5 }}`
+
+	out, err := NewEngine().ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "-9223372036854775808 2 9223372036854775806 9.223372036854776e+18", out)
+
+	engine := NewEngine()
+	engine.PromoteIntOverflow()
+	out, err = engine.ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "9223372036854775808 -18446744073709551614 9223372036854775806 9.223372036854776e+18", out)
+
+	out, err = engine.ParseAndRenderString(`{% assign n = max | plus: 1 %}{{ n | minus: 1 | minus: 1 }}{% if n > max %} greater{% endif %}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "9223372036854775806 greater", out)
+
+	// a filter that the application registers isn't replaced
+	engine = NewEngine()
+	engine.RegisterFilter("plus", func(a, b int) string { return "custom" })
+	engine.PromoteIntOverflow()
+	out, err = engine.ParseAndRenderString(`{{ max | plus: 1 }} {{ max | times: 2 }}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "custom 18446744073709551614", out)
+}
+
+func TestEngine_SetTimeLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	engine := NewEngine()
+	engine.SetTimeLocation(tokyo)
+	engine.SetClock(func() time.Time { return time.Date(2024, 12, 31, 16, 0, 0, 0, time.UTC) })
+
+	bindings := Bindings{"t": time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	out, err := engine.ParseAndRenderString(`{{ t | date: "%H:%M" }} {{ t | date: "%H:%M", "UTC" }} {{ "2024-03-01 09:00" | date: "%H:%M %z" }} {{ "now" | date: "%Y" }}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "21:00 12:00 09:00 +0900 2025", out)
+
+	// a date filter that the application registers isn't replaced
+	engine = NewEngine()
+	engine.RegisterFilter("date", func(any) string { return "custom" })
+	engine.SetTimeLocation(tokyo)
+	engine.SetClock(time.Now)
+	out, err = engine.ParseAndRenderString(`{{ t | date }}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "custom", out)
+}
+
+func TestEngine_SetCharacterMode(t *testing.T) {
+	const src = `{{ post.title.size }} {{ post.title | size }} {{ post.title.last }} {{ post.title | truncate: 4, "" }}`
+
+	bindings := Bindings{"post": map[string]any{"title": "cafe\u0301"}}
+
+	out, err := NewEngine().ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "5 5 \u0301 cafe", out)
+
+	engine := NewEngine()
+	engine.SetCharacterMode(GraphemeCharacters)
+	out, err = engine.ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "4 4 e\u0301 cafe\u0301", out)
+
+	// a filter that the application registers isn't replaced
+	engine = NewEngine()
+	engine.RegisterFilter("size", func(any) string { return "custom" })
+	engine.SetCharacterMode(GraphemeCharacters)
+	out, err = engine.ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "4 custom e\u0301 cafe\u0301", out)
+}
+
+func TestEngine_SetWarningHandler(t *testing.T) {
+	var warnings []render.Warning
+
+	engine := NewEngine()
+	engine.SetWarningHandler(func(w render.Warning) { warnings = append(warnings, w) })
+	tpl, err := engine.ParseTemplateLocation([]byte("line 1\n{% if n < \"one\" %}yes{% endif %}{{ n > 'x' }}"), "test.liquid", 1)
+	require.NoError(t, err)
+
+	out, err := tpl.RenderString(Bindings{"n": 1})
+	require.NoError(t, err)
+	require.Equal(t, "line 1\nfalse", out)
+	require.Len(t, warnings, 2)
+	require.Equal(t, "test.liquid", warnings[0].Pathname)
+	require.Equal(t, 2, warnings[0].LineNo)
+	require.Equal(t, `{% if n < "one" %}`, warnings[0].Source)
+	require.Contains(t, warnings[0].Message, "can't be ordered")
+	require.Equal(t, `{{ n > 'x' }}`, warnings[1].Source)
+	require.Contains(t, warnings[1].String(), "test.liquid:2")
+
+	_, ws, err := tpl.RenderWithWarnings(Bindings{"n": 1})
+	require.NoError(t, err)
+	require.Len(t, ws, 2)
+	require.Len(t, warnings, 4)
+}
+
+type filterCounter struct {
+	render.NopTracer
+
+	counts map[string]int
+}
+
+func (c *filterCounter) ExitFilter(e render.FilterEvent) { c.counts[e.Name]++ }
+
+func TestEngine_SetTracer(t *testing.T) {
+	tracer := &filterCounter{counts: map[string]int{}}
+	engine := NewEngine()
+	engine.SetTracer(tracer)
+
+	out, err := engine.ParseAndRenderString(`{% for s in list %}{{ s | upcase | append: "." }}{% endfor %}`, Bindings{"list": []string{"a", "b"}})
+	require.NoError(t, err)
+	require.Equal(t, "A.B.", out)
+	require.Equal(t, map[string]int{"upcase": 2, "append": 2}, tracer.counts)
+}
+
+func TestEngine_SetFieldNamer(t *testing.T) {
+	type variant struct {
+		CompareAtPrice int
+		SKU            string `json:"sku_code"`
+	}
+
+	type product struct {
+		Title    string `json:"name"`
+		Variants []variant
+	}
+
+	bindings := Bindings{"product": product{"Hat", []variant{{12, "H1"}}}}
+	src := `{{ product.title }} {% for v in product.variants %}{{ v.compare_at_price }} {{ v["sku"] }}{% endfor %} {% if product contains "variants" %}ok{% endif %}`
+
+	engine := NewEngine()
+	engine.SetFieldNamer(SnakeCaseFieldNames)
+	out, err := engine.ParseAndRenderString(src, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "Hat 12 H1 ok", out)
+
+	variants := Bindings{"vs": []variant{{20, "B"}, {10, "A"}}}
+	out, err = engine.ParseAndRenderString(`{{ vs | map: "compare_at_price" | join: "," }} {{ vs | sort: "compare_at_price" | map: "sku" | join }}`, variants)
+	require.NoError(t, err)
+	require.Equal(t, "20,10 A B", out)
+
+	engine.SetFieldNamer(JSONFieldNames)
+	out, err = engine.ParseAndRenderString(`{{ product.name }} {{ product.Variants.first.sku_code }}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "Hat H1", out)
+
+	engine.SetFieldNamer(func(f reflect.StructField) string { return strings.ToUpper(f.Name) })
+	out, err = engine.ParseAndRenderString(`{{ product.TITLE }}{{ product.Title }}`, bindings)
+	require.NoError(t, err)
+	require.Equal(t, "Hat", out)
+}
+
+func TestEngine_EnableFrontMatter(t *testing.T) {
+	src := "---\ntitle: About\nauthor:\n  name: Alice\n---\n<h1>{{ page.title }}</h1>\n{{ page.author.name }}"
+
+	// without front matter
+	tpl, err := NewEngine().ParseString(src)
+	require.NoError(t, err)
+	require.Nil(t, tpl.FrontMatter())
+
+	engine := NewEngine()
+	engine.EnableFrontMatter()
+	tpl, err = engine.ParseString(src)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"title": "About", "author": yaml.MapSlice{{Key: "name", Value: "Alice"}}}, tpl.FrontMatter())
+
+	out, err := tpl.RenderString(Bindings{})
+	require.NoError(t, err)
+	require.Equal(t, "<h1>About</h1>\nAlice", out)
+
+	// bindings take precedence, and are merged with the front matter
+	out, err = tpl.RenderString(Bindings{"page": map[string]any{"title": "Home"}})
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Home</h1>\nAlice", out)
+
+	// error line numbers are those in the source
+	tpl, err = engine.ParseTemplateLocation([]byte("---\ntitle: x\n---\nline 4\n{{ page.title | undefined_filter }}"), "page.html", 1)
+	require.NoError(t, err)
+	_, err = tpl.Render(Bindings{})
+	require.Error(t, err)
+	require.Equal(t, 5, err.(SourceError).LineNumber())
+
+	_, err = engine.ParseTemplateLocation([]byte("---\ntitle: x\n---\n{% if %}"), "page.html", 1)
+	require.Error(t, err)
+	require.Equal(t, 4, err.(SourceError).LineNumber())
+
+	// invalid front matter
+	_, err = engine.ParseString("---\n- a\n---\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mapping")
+	_, err = engine.ParseString("---\ntitle: [\n---\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "front matter")
+
+	engine.SetFrontMatterVariable("meta")
+	out, err = engine.ParseAndRenderString("---\ntitle: About\n---\n{{ meta.title }}{{ page.title }}", Bindings{})
+	require.NoError(t, err)
+	require.Equal(t, "About", out)
+}
+
+func TestEngine_EvaluateString(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterFilter("twice", func(n int) int { return 2 * n })
+
+	value, err := engine.EvaluateString(`list | size | twice`, Bindings{"list": []int{1, 2, 3}})
+	require.NoError(t, err)
+	require.Equal(t, 6, value)
+
+	_, err = engine.EvaluateString(`x | undefined_filter`, Bindings{})
+	require.Error(t, err)
+}
+
+func TestEngine_ParseAST(t *testing.T) {
+	engine := NewEngine()
+	engine.EnableFrontMatter()
+
+	root, err := engine.ParseAST([]byte("---\nx: 1\n---\n{% if x %}{{ y }}{% endif %}"), "page.html", 1)
+	require.NoError(t, err)
+	require.IsType(t, &parser.ASTSeq{}, root)
+
+	children := root.(*parser.ASTSeq).Children
+	require.Len(t, children, 1)
+	require.IsType(t, &parser.ASTBlock{}, children[0])
+	require.Equal(t, "if", children[0].(*parser.ASTBlock).Name)
+	require.Equal(t, parser.SourceLoc{Pathname: "page.html", LineNo: 4}, children[0].SourceLocation())
+
+	_, err = engine.ParseAST([]byte("{% if x %}"), "page.html", 1)
+	require.Error(t, err)
+}
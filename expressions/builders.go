@@ -15,7 +15,7 @@ func makeRangeExpr(startFn, endFn func(Context) values.Value) func(Context) valu
 
 func makeContainsExpr(e1, e2 func(Context) values.Value) func(Context) values.Value {
 	return func(ctx Context) values.Value {
-		return values.ValueOf(e1(ctx).Contains(e2(ctx)))
+		return values.ValueOf(withValueOptions(ctx, e1(ctx)).Contains(e2(ctx)))
 	}
 }
 
@@ -29,20 +29,57 @@ func makeFilter(fn valueFn, name string, args []valueFn) valueFn {
 			})
 		}
 
-		return values.ValueOf(result)
+		return resolveDrop(ctx, values.ValueOf(result))
 	}
 }
 
 func makeIndexExpr(sequenceFn, indexFn func(Context) values.Value) func(Context) values.Value {
-	return func(ctx Context) values.Value {
-		return sequenceFn(ctx).IndexValue(indexFn(ctx))
+	return func(ctx Context) (result values.Value) {
+		seq, index := withValueOptions(ctx, sequenceFn(ctx)), indexFn(ctx)
+		if warningsEnabled(ctx) {
+			defer recoverMethodPanic(ctx, &result)
+		}
+
+		return resolveDrop(ctx, traceDrop(ctx, seq.IndexValue(index)))
 	}
 }
 
 func makeObjectPropertyExpr(objFn func(Context) values.Value, name string) func(Context) values.Value {
 	index := values.ValueOf(name)
 
-	return func(ctx Context) values.Value {
-		return objFn(ctx).PropertyValue(index)
+	return func(ctx Context) (result values.Value) {
+		obj := withValueOptions(ctx, objFn(ctx))
+		if warningsEnabled(ctx) {
+			defer recoverMethodPanic(ctx, &result)
+		}
+
+		return resolveDrop(ctx, traceDrop(ctx, obj.PropertyValue(index)))
+	}
+}
+
+// recoverMethodPanic turns a panic in a struct method into a warning and a nil result.
+// It must be called directly by defer.
+func recoverMethodPanic(ctx Context, result *values.Value) {
+	if r := recover(); r != nil {
+		e, ok := r.(values.MethodPanic)
+		if !ok {
+			panic(r)
+		}
+
+		warnf(ctx, "%s", e)
+
+		*result = values.ValueOf(nil)
+	}
+}
+
+// warnIfUnordered warns if a and b can't be ordered; for example, 1 < "one" is always false.
+func warnIfUnordered(ctx Context, a, b values.Value) {
+	if !warningsEnabled(ctx) {
+		return
+	}
+
+	x, y := a.Interface(), b.Interface()
+	if x != nil && y != nil && !values.Comparable(x, y) {
+		warnf(ctx, "%T and %T can't be ordered; < and > between them are always false", x, y)
 	}
 }
@@ -1,10 +1,51 @@
 package expressions
 
-import "sort"
+import (
+	"sort"
+	"time"
+
+	"github.com/osteele/liquid/values"
+)
 
 // Config holds configuration information for expression interpretation.
 type Config struct {
 	filters map[string]any
+
+	// WarningHandler, if non-nil, is called with a description of operations
+	// that succeed but are probably mistakes, such as comparing a number to a string.
+	WarningHandler func(message string)
+
+	// FilterHook, if non-nil, is called before each filter application with the filter
+	// name and its arguments, starting with its input. If it returns a non-nil function,
+	// that function is called after the application, with its error if any.
+	FilterHook func(name string, args []any) func(err error)
+	// DropHook, if non-nil, is called when a drop is converted to a Liquid value,
+	// with the time that its ToLiquid method took.
+	DropHook func(drop any, elapsed time.Duration)
+	// ResolveDrop, if non-nil, is called with the values of variables, properties,
+	// indices and filter results, and with DropSource. If the value is a drop that
+	// it can resolve, it returns the value that the drop stands for, and true.
+	ResolveDrop func(value, source any) (any, bool)
+	// DropSource is passed to ResolveDrop; for example, the template node whose
+	// expressions are evaluated.
+	DropSource any
+	// StructNaming, if non-nil, resolves the property names of structs to their
+	// fields and methods. If it is nil, they are accessed by their Go names.
+	StructNaming *values.StructNaming
+	// CharacterMode determines what the size, first and last properties of
+	// strings count as characters.
+	CharacterMode values.CharacterMode
+	// PromoteIntOverflow causes the plus, minus and times filters to return a
+	// *big.Int when the result of an operation on two integers overflows an int64.
+	PromoteIntOverflow bool
+	// TimeLocation, if non-nil, is the time zone in which the date filter parses
+	// dates without a time zone, and formats all dates. If it is nil, dates without
+	// a time zone are in the local time zone, and other dates are formatted in
+	// their own time zone.
+	TimeLocation *time.Location
+	// Clock, if non-nil, returns the current time, for the "now" and "today"
+	// inputs to the date filter. If it is nil, time.Now is used.
+	Clock func() time.Time
 }
 
 // NewConfig creates a new Config.
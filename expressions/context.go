@@ -1,6 +1,11 @@
 package expressions
 
-import "github.com/osteele/liquid/values"
+import (
+	"fmt"
+	"time"
+
+	"github.com/osteele/liquid/values"
+)
 
 // Context is the expression evaluation context. It maps variables names to values.
 type Context interface {
@@ -34,10 +39,115 @@ func (ctx *context) Clone() Context {
 
 // Get looks up a variable value in the expression context.
 func (ctx *context) Get(name string) any {
-	return values.ToLiquid(ctx.bindings[name])
+	value := ctx.bindings[name]
+
+	var start time.Time
+	if ctx.DropHook != nil {
+		start = time.Now()
+	}
+
+	result, isDrop := ctx.resolve(value)
+	if isDrop && ctx.DropHook != nil {
+		ctx.DropHook(value, time.Since(start))
+	}
+
+	if isDrop && ctx.WarningHandler != nil && !values.IsLiquidType(result) {
+		warnf(ctx, "%s: ToLiquid of %T returned unsupported type %T", name, value, result)
+	}
+
+	return result
+}
+
+// resolve returns the Liquid value of a variable value, and whether the variable value is a drop.
+func (ctx *context) resolve(value any) (any, bool) {
+	if ctx.ResolveDrop != nil {
+		if result, ok := ctx.ResolveDrop(value, ctx.DropSource); ok {
+			return result, true
+		}
+	}
+
+	if d, ok := value.(interface{ ToLiquid() any }); ok {
+		return d.ToLiquid(), true
+	}
+
+	return value, false
 }
 
 // Set sets a variable value in the expression context.
 func (ctx *context) Set(name string, value any) {
 	ctx.bindings[name] = value
 }
+
+// warnf reports a non-fatal issue to the context's warning handler, if there is one.
+func warnf(ctx Context, format string, a ...any) {
+	if c, ok := ctx.(*context); ok && c.WarningHandler != nil {
+		c.WarningHandler(fmt.Sprintf(format, a...))
+	}
+}
+
+// warningsEnabled returns a bool indicating whether ctx reports warnings.
+func warningsEnabled(ctx Context) bool {
+	c, ok := ctx.(*context)
+	return ok && c.WarningHandler != nil
+}
+
+// resolveDrop returns the value that v stands for, if it is a drop that the context's
+// ResolveDrop function resolves.
+func resolveDrop(ctx Context, v values.Value) values.Value {
+	c, ok := ctx.(*context)
+	if !ok || c.ResolveDrop == nil {
+		return v
+	}
+
+	value := v.Interface()
+
+	var start time.Time
+	if c.DropHook != nil {
+		start = time.Now()
+	}
+
+	result, ok := c.ResolveDrop(value, c.DropSource)
+	if !ok {
+		return v
+	}
+
+	if c.DropHook != nil {
+		c.DropHook(value, time.Since(start))
+	}
+
+	return values.ValueOf(result)
+}
+
+// withValueOptions returns v, with its struct properties resolved by the context's
+// StructNaming, and its string properties counted by the context's CharacterMode.
+// If warnings are enabled, a panic in a struct method is reported as a
+// MethodPanic, for recoverMethodPanic.
+func withValueOptions(ctx Context, v values.Value) values.Value {
+	c, ok := ctx.(*context)
+	if !ok {
+		return v
+	}
+
+	if c.StructNaming != nil {
+		v = values.WithStructNaming(v, c.StructNaming)
+	}
+
+	if c.CharacterMode != values.RuneCharacters {
+		v = values.WithCharacterMode(v, c.CharacterMode)
+	}
+
+	if c.WarningHandler != nil {
+		v = values.WithMethodPanics(v)
+	}
+
+	return v
+}
+
+// traceDrop arranges for the context's drop hook, if any, to be called when v is resolved.
+func traceDrop(ctx Context, v values.Value) values.Value {
+	if c, ok := ctx.(*context); ok && c.DropHook != nil {
+		return values.TraceDrop(v, c.DropHook)
+	}
+
+	return v
+}
@@ -165,6 +165,7 @@ rel:
 	fa, fb := $1, $3
 	$$ = func(ctx Context) values.Value {
 		a, b := fa(ctx), fb(ctx)
+		warnIfUnordered(ctx, a, b)
 		return values.ValueOf(b.Less(a))
 	}
 }
@@ -172,6 +173,7 @@ rel:
 	fa, fb := $1, $3
 	$$ = func(ctx Context) values.Value {
 		a, b := fa(ctx), fb(ctx)
+		warnIfUnordered(ctx, a, b)
 		return values.ValueOf(a.Less(b))
 	}
 }
@@ -179,6 +181,7 @@ rel:
 	fa, fb := $1, $3
 	$$ = func(ctx Context) values.Value {
 		a, b := fa(ctx), fb(ctx)
+		warnIfUnordered(ctx, a, b)
 		return values.ValueOf(b.Less(a) || a.Equal(b))
 	}
 }
@@ -186,6 +189,7 @@ rel:
 	fa, fb := $1, $3
 	$$ = func(ctx Context) values.Value {
 		a, b := fa(ctx), fb(ctx)
+		warnIfUnordered(ctx, a, b)
 		return values.ValueOf(a.Less(b) || a.Equal(b))
 	}
 }
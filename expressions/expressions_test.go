@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/osteele/liquid/values"
 	"github.com/stretchr/testify/require"
@@ -171,3 +172,91 @@ func TestClosure(t *testing.T) {
 	require.Equal(t, 1, x1)
 	require.Equal(t, 2, x2)
 }
+
+type panickingStruct struct{}
+
+func (panickingStruct) Fail() int { panic("fail") }
+
+type funcDrop struct{}
+
+func (funcDrop) ToLiquid() any { return func() {} }
+
+func TestWarnings(t *testing.T) {
+	var warnings []string
+
+	cfg := NewConfig()
+	cfg.WarningHandler = func(msg string) { warnings = append(warnings, msg) }
+	cfg.AddFilter("append", func(s, suffix string) string { return s + suffix })
+	ctx := NewContext(map[string]any{"obj": panickingStruct{}, "drop": funcDrop{}}, cfg)
+
+	tests := []struct {
+		in, warning string
+		expected    any
+	}{
+		{`1 < "one"`, "int and string can't be ordered", false},
+		{`1 >= "one"`, "int and string can't be ordered", false},
+		{`"a" | append: undefined`, `filter "append" argument 1 is nil`, "a"},
+		{`obj.Fail`, "Fail panicked: fail", nil},
+		{`drop`, "returned unsupported type func()", nil},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i+1), func(t *testing.T) {
+			warnings = nil
+			val, err := EvaluateString(test.in, ctx)
+			require.NoErrorf(t, err, test.in)
+			if test.expected != nil {
+				require.Equalf(t, test.expected, val, test.in)
+			}
+			require.Lenf(t, warnings, 1, test.in)
+			require.Containsf(t, warnings[0], test.warning, test.in)
+		})
+	}
+
+	warnings = nil
+	_, err := EvaluateString(`1 < 2 and "a" < "b" and undefined < 1`, ctx)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// without a handler, a panicking method panics with its original value
+	ctx = NewContext(map[string]any{"obj": panickingStruct{}}, NewConfig())
+	require.PanicsWithValue(t, "fail", func() { _, _ = EvaluateString(`obj.Fail`, ctx) })
+}
+
+type valueDrop struct{ value any }
+
+func (d valueDrop) ToLiquid() any { return d.value }
+
+func TestHooks(t *testing.T) {
+	var (
+		filters []string
+		drops   []any
+	)
+
+	cfg := NewConfig()
+	cfg.FilterHook = func(name string, args []any) func(error) {
+		return func(err error) { filters = append(filters, fmt.Sprintf("%s%v %v", name, args, err)) }
+	}
+	cfg.DropHook = func(drop any, _ time.Duration) { drops = append(drops, drop) }
+	cfg.AddFilter("append", func(s, suffix string) string { return s + suffix })
+	cfg.AddFilter("fail", func(any) (any, error) { return nil, fmt.Errorf("failed") })
+	cfg.AddFilter("panic", func(any) any { panic("panicked") })
+	ctx := NewContext(map[string]any{
+		"drop": valueDrop{"a"},
+		"map":  map[string]any{"drop": valueDrop{"b"}},
+	}, cfg)
+
+	val, err := EvaluateString(`drop | append: map.drop`, ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ab", val)
+	require.Equal(t, []string{"append[a b] <nil>"}, filters)
+	require.Equal(t, []any{valueDrop{"a"}, valueDrop{"b"}}, drops)
+
+	filters = nil
+	_, err = EvaluateString(`1 | fail`, ctx)
+	require.Error(t, err)
+	require.Equal(t, []string{"fail[1] failed"}, filters)
+
+	filters = nil
+	require.Panics(t, func() { _, _ = EvaluateString(`1 | panic`, ctx) })
+	require.Equal(t, []string{"panic[1] panicked"}, filters)
+}
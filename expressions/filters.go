@@ -33,6 +33,36 @@ func (e FilterError) Error() string {
 
 type valueFn func(Context) values.Value
 
+// A FilterContext gives a filter access to the configuration that it is applied with.
+// If the first parameter of a filter function has this type, the function is called
+// with the context, followed by the filter input and arguments.
+type FilterContext struct {
+	ctx *context
+}
+
+// Config returns the configuration that the filter is applied with.
+func (fc FilterContext) Config() Config {
+	return fc.ctx.Config
+}
+
+// ValueOf returns the Liquid value of v, with its struct properties named, and
+// its string properties counted, as the configuration specifies.
+func (fc FilterContext) ValueOf(v any) values.Value {
+	return withValueOptions(fc.ctx, values.ValueOf(v))
+}
+
+var filterContextType = reflect.TypeOf(FilterContext{})
+
+// filterInputIndex returns the index of the parameter of a filter function that
+// receives the filter input.
+func filterInputIndex(t reflect.Type) int {
+	if t.NumIn() > 0 && t.In(0) == filterContextType {
+		return 1
+	}
+
+	return 0
+}
+
 func (c *Config) ensureMapIsCreated() {
 	if c.filters == nil {
 		c.filters = make(map[string]interface{})
@@ -45,7 +75,7 @@ func (c *Config) AddFilter(name string, fn any) {
 	switch {
 	case rf.Kind() != reflect.Func:
 		panic("a filter must be a function")
-	case rf.Type().NumIn() < 1:
+	case rf.Type().NumIn() < filterInputIndex(rf.Type())+1:
 		panic("a filter function must have at least one input")
 	case rf.Type().NumOut() < 1 || 2 < rf.Type().NumOut():
 		panic("a filter must be have one or two outputs")
@@ -80,6 +110,19 @@ func isClosureInterfaceType(t reflect.Type) bool {
 	return closureType.ConvertibleTo(t) && !interfaceType.ConvertibleTo(t)
 }
 
+// isScalarKind returns a bool indicating whether nil converts to the kind's zero value,
+// instead of to a nil interface, pointer, or container.
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ctx *context) ApplyFilter(name string, receiver valueFn, params []valueFn) (any, error) {
 	filter, ok := ctx.filters[name]
 	if !ok {
@@ -88,9 +131,10 @@ func (ctx *context) ApplyFilter(name string, receiver valueFn, params []valueFn)
 
 	fr := reflect.ValueOf(filter)
 	args := []any{receiver(ctx).Interface()}
+	input := filterInputIndex(fr.Type())
 
 	for i, param := range params {
-		if i+1 < fr.Type().NumIn() && isClosureInterfaceType(fr.Type().In(i+1)) {
+		if n := input + i + 1; n < fr.Type().NumIn() && isClosureInterfaceType(fr.Type().In(n)) {
 			expr, err := Parse(param(ctx).Interface().(string))
 			if err != nil {
 				panic(err)
@@ -98,16 +142,17 @@ func (ctx *context) ApplyFilter(name string, receiver valueFn, params []valueFn)
 
 			args = append(args, closure{expr, ctx})
 		} else {
-			args = append(args, param(ctx).Interface())
+			arg := param(ctx).Interface()
+			if n := input + i + 1; arg == nil && n < fr.Type().NumIn() && isScalarKind(fr.Type().In(n).Kind()) {
+				warnf(ctx, "filter %q argument %d is nil; using %s zero value", name, i+1, fr.Type().In(n))
+			}
+
+			args = append(args, arg)
 		}
 	}
 
-	out, err := values.Call(fr, args)
+	out, err := ctx.callFilter(name, fr, args)
 	if err != nil {
-		if e, ok := err.(*values.CallParityError); ok {
-			err = &values.CallParityError{NumArgs: e.NumArgs - 1, NumParams: e.NumParams - 1}
-		}
-
 		return nil, err
 	}
 
@@ -118,3 +163,37 @@ func (ctx *context) ApplyFilter(name string, receiver valueFn, params []valueFn)
 		return out, nil
 	}
 }
+
+// callFilter calls a filter function, and reports the call to the context's filter hook.
+func (ctx *context) callFilter(name string, fr reflect.Value, args []any) (out any, err error) {
+	if ctx.FilterHook != nil {
+		if done := ctx.FilterHook(name, args); done != nil {
+			defer func() {
+				if r := recover(); r != nil {
+					e, ok := r.(error)
+					if !ok {
+						e = fmt.Errorf("%v", r)
+					}
+
+					done(e)
+					panic(r)
+				}
+
+				done(err)
+			}()
+		}
+	}
+
+	input := filterInputIndex(fr.Type())
+	if input > 0 {
+		args = append([]any{FilterContext{ctx}}, args...)
+	}
+
+	out, err = values.Call(fr, args)
+	if e, ok := err.(*values.CallParityError); ok {
+		// don't count the filter context and input
+		err = &values.CallParityError{NumArgs: e.NumArgs - input - 1, NumParams: e.NumParams - input - 1}
+	}
+
+	return out, err
+}
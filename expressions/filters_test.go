@@ -14,6 +14,7 @@ func TestContext_AddFilter(t *testing.T) {
 	require.NotPanics(t, func() { cfg.AddFilter("f", func(int) int { return 0 }) })
 	require.NotPanics(t, func() { cfg.AddFilter("f", func(int) (a int, e error) { return }) })
 	require.Panics(t, func() { cfg.AddFilter("f", func() int { return 0 }) })
+	require.Panics(t, func() { cfg.AddFilter("f", func(FilterContext) int { return 0 }) })
 	require.Panics(t, func() { cfg.AddFilter("f", func(int) {}) })
 	// require.Panics(t, func() { cfg.AddFilter("f", func(int) (a int, b int) { return }) })
 	//nolint:staticcheck
@@ -68,6 +69,20 @@ func TestContext_runFilter(t *testing.T) {
 
 		return fmt.Sprintf("(%v, %v)", a, value), nil
 	})
+	// filter context
+	cfg.AddFilter("with_context", func(fc FilterContext, a string, n int) string {
+		return fmt.Sprintf("(%s, %d, %v)", a, n, fc.Config().CharacterMode)
+	})
+	cfg.CharacterMode = values.GraphemeCharacters
+	ctx = NewContext(map[string]any{"x": 10}, cfg)
+	out, err = ctx.ApplyFilter("with_context", receiver, []valueFn{constant(1)})
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("(self, 1, %v)", values.GraphemeCharacters), out)
+	_, err = ctx.ApplyFilter("with_context", receiver, []valueFn{constant(1), constant(2)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "given 2")
+	require.Contains(t, err.Error(), "expected 1")
+
 	ctx = NewContext(map[string]any{"x": 10}, cfg)
 	out, err = ctx.ApplyFilter("closure", receiver, []valueFn{constant("x |add: y")})
 	require.NoError(t, err)
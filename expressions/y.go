@@ -780,48 +780,52 @@ yydefault:
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
 				a, b := fa(ctx), fb(ctx)
+				warnIfUnordered(ctx, a, b)
 				return values.ValueOf(b.Less(a))
 			}
 		}
 	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:171
+//line expressions.y:172
 		{
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
 				a, b := fa(ctx), fb(ctx)
+				warnIfUnordered(ctx, a, b)
 				return values.ValueOf(a.Less(b))
 			}
 		}
 	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:178
+//line expressions.y:180
 		{
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
 				a, b := fa(ctx), fb(ctx)
+				warnIfUnordered(ctx, a, b)
 				return values.ValueOf(b.Less(a) || a.Equal(b))
 			}
 		}
 	case 39:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:185
+//line expressions.y:188
 		{
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
 				a, b := fa(ctx), fb(ctx)
+				warnIfUnordered(ctx, a, b)
 				return values.ValueOf(a.Less(b) || a.Equal(b))
 			}
 		}
 	case 40:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:192
+//line expressions.y:196
 		{
 			yyVAL.f = makeContainsExpr(yyDollar[1].f, yyDollar[3].f)
 		}
 	case 42:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:197
+//line expressions.y:201
 		{
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
@@ -830,7 +834,7 @@ yydefault:
 		}
 	case 43:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line expressions.y:203
+//line expressions.y:207
 		{
 			fa, fb := yyDollar[1].f, yyDollar[3].f
 			yyVAL.f = func(ctx Context) values.Value {
@@ -0,0 +1,130 @@
+package filters
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/values"
+	"github.com/osteele/tuesday"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	unixTimestamp = regexp.MustCompile(`^-?\d+$`)
+	zoneOffset    = regexp.MustCompile(`^([+-])(\d\d):?(\d\d)$`)
+)
+
+// dateFilter formats its input with a strftime format, and an optional time zone
+// name, as in {{ order.created_at | date: "%H:%M", "Europe/Paris" }}. Its input
+// can be a time.Time, a string that values.ParseDate recognizes, "now" or "today",
+// or a Unix timestamp as a number or a string of digits. The configuration's
+// TimeLocation and Clock determine the default time zone and the current time.
+func dateFilter(fc expressions.FilterContext, value any, format func(string) string, zone func(string) string) (string, error) {
+	t, err := toTime(value, fc.Config())
+	if err != nil {
+		return "", err
+	}
+
+	if name := zone(""); name != "" {
+		loc, err := loadLocation(name)
+		if err != nil {
+			return "", err
+		}
+
+		t = t.In(loc)
+	}
+
+	f := format("%a, %b %d, %y")
+
+	return tuesday.Strftime(f, t)
+}
+
+// toTime converts the input of the date filter to a time.
+func toTime(value any, cfg expressions.Config) (time.Time, error) {
+	value = values.ToLiquid(value)
+
+	var t time.Time
+
+	switch v := value.(type) {
+	case nil:
+		return t, nil
+	case time.Time:
+		t = v
+	case string:
+		switch {
+		case v == "now" || v == "today":
+			t = currentTime(cfg)
+		case unixTimestamp.MatchString(v):
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return t, err
+			}
+
+			t = time.Unix(n, 0)
+		default:
+			loc := cfg.TimeLocation
+			if loc == nil {
+				loc = time.Local
+			}
+
+			var err error
+			if t, err = values.ParseDateInLocation(v, loc); err != nil {
+				return t, err
+			}
+		}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		t = time.Unix(toInt64(v), 0)
+	case float32, float64:
+		sec, frac := math.Modf(toFloat64(v))
+		t = time.Unix(int64(sec), int64(frac*1e9))
+	default:
+		c, err := values.Convert(value, timeType)
+		if err != nil {
+			return t, err
+		}
+
+		t = c.(time.Time)
+	}
+
+	if cfg.TimeLocation != nil {
+		t = t.In(cfg.TimeLocation)
+	}
+
+	return t, nil
+}
+
+func currentTime(cfg expressions.Config) time.Time {
+	if cfg.Clock != nil {
+		return cfg.Clock()
+	}
+
+	return time.Now()
+}
+
+// loadLocation returns the time zone with an IANA name such as "America/New_York",
+// "UTC" or "Local", or a numeric offset such as "+05:30" or "-0800".
+func loadLocation(name string) (*time.Location, error) {
+	if m := zoneOffset.FindStringSubmatch(name); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+
+		offset := (hours*60 + minutes) * 60
+		if m[1] == "-" {
+			offset = -offset
+		}
+
+		return time.FixedZone(name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+
+	return loc, nil
+}
@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/osteele/liquid/expressions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateFilter(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	bindings := map[string]any{
+		"utc":       time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		"timestamp": 1709294400,
+	}
+
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		location *time.Location
+		clock    func() time.Time
+		in       string
+		expected string
+	}{
+		{nil, nil, `utc | date: "%H:%M %Z"`, "12:00 UTC"},
+		{nil, nil, `utc | date: "%H:%M", "Asia/Tokyo"`, "21:00"},
+		{nil, nil, `utc | date: "%H:%M %z", "-05:00"`, "07:00 -0500"},
+		{nil, nil, `utc | date: "%H:%M %z", "+0530"`, "17:30 +0530"},
+		{nil, nil, `timestamp | date: "%Y-%m-%d %H:%M", "UTC"`, "2024-03-01 12:00"},
+		{nil, nil, `"1709294400" | date: "%Y-%m-%d %H:%M", "UTC"`, "2024-03-01 12:00"},
+		{nil, nil, `1709294400.5 | date: "%H:%M:%S.%L", "UTC"`, "12:00:00.500"},
+		{nil, clock, `"now" | date: "%Y-%m-%d %H:%M"`, "2024-03-01 23:30"},
+		{nil, clock, `"today" | date: "%Y-%m-%d"`, "2024-03-01"},
+
+		// a default location
+		{tokyo, nil, `utc | date: "%H:%M %Z"`, "21:00 JST"},
+		{tokyo, nil, `timestamp | date: "%H:%M"`, "21:00"},
+		{tokyo, nil, `"2024-03-01 09:00" | date: "%H:%M %z"`, "09:00 +0900"},
+		{tokyo, nil, `"2024-03-01T09:00:00Z" | date: "%H:%M"`, "18:00"},
+		{tokyo, nil, `utc | date: "%H:%M", "UTC"`, "12:00"},
+		{tokyo, clock, `"today" | date: "%Y-%m-%d"`, "2024-03-02"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			cfg := expressions.NewConfig()
+			cfg.TimeLocation, cfg.Clock = test.location, test.clock
+			AddStandardFilters(&cfg)
+
+			actual, err := expressions.EvaluateString(test.in, expressions.NewContext(bindings, cfg))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	cfg := expressions.NewConfig()
+	AddStandardFilters(&cfg)
+	_, err = expressions.EvaluateString(`utc | date: "%H", "Nowhere/Special"`, expressions.NewContext(bindings, cfg))
+	require.ErrorContains(t, err, "unknown time zone")
+}
@@ -0,0 +1,167 @@
+package filters
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/values"
+)
+
+// The arithmetic filters keep the type of their operands: the result of an
+// operation on *big.Int, *big.Rat, *big.Float or Decimal values is computed by
+// the values package, as the greater of the operands' types.
+
+var float64Type = reflect.TypeOf(float64(0))
+
+// floatFilter applies fn to a, converted to a float64.
+func floatFilter[T any](a any, fn func(float64) T) (any, error) {
+	if a == nil {
+		return fn(0), nil
+	}
+
+	f, err := values.Convert(a, float64Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(f.(float64)), nil
+}
+
+func minusFilter(a, b any) (any, error) {
+	if values.IsBigNumber(a) || values.IsBigNumber(b) {
+		return values.BigSub(bigOperand(a), bigOperand(b))
+	}
+	// If both operands are integers, perform integer arithmetic
+	if isIntegerType(a) && isIntegerType(b) {
+		return toInt64(a) - toInt64(b), nil
+	}
+	// Otherwise, perform float arithmetic
+	return toFloat64(a) - toFloat64(b), nil
+}
+
+func plusFilter(a, b any) (any, error) {
+	if values.IsBigNumber(a) || values.IsBigNumber(b) {
+		return values.BigAdd(bigOperand(a), bigOperand(b))
+	}
+	// If both operands are integers, perform integer arithmetic
+	if isIntegerType(a) && isIntegerType(b) {
+		return toInt64(a) + toInt64(b), nil
+	}
+	// Otherwise, perform float arithmetic
+	return toFloat64(a) + toFloat64(b), nil
+}
+
+func timesFilter(a, b any) (any, error) {
+	if values.IsBigNumber(a) || values.IsBigNumber(b) {
+		return values.BigMul(bigOperand(a), bigOperand(b))
+	}
+	// If both operands are integers, perform integer arithmetic
+	if isIntegerType(a) && isIntegerType(b) {
+		return toInt64(a) * toInt64(b), nil
+	}
+	// Otherwise, perform float arithmetic
+	return toFloat64(a) * toFloat64(b), nil
+}
+
+func dividedByFilter(a, b any) (any, error) {
+	if values.IsBigNumber(a) || values.IsBigNumber(b) {
+		if !isNumber(a) {
+			f, err := toDividend(a)
+			if err != nil {
+				return nil, err
+			}
+
+			a = f
+		}
+
+		return values.BigQuo(a, b)
+	}
+
+	dividend, err := toDividend(a)
+	if err != nil {
+		return nil, err
+	}
+
+	divInt := func(a, b int64) (int64, error) {
+		if b == 0 {
+			return 0, errDivisionByZero
+		}
+
+		return a / b, nil
+	}
+
+	divFloat := func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, errDivisionByZero
+		}
+
+		return a / b, nil
+	}
+	switch q := b.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return divInt(int64(dividend), toInt64(q))
+	case float32, float64:
+		return divFloat(dividend, toFloat64(q))
+	default:
+		return nil, fmt.Errorf("invalid divisor: '%v'", b)
+	}
+}
+
+// toDividend converts the input of the divided_by filter to a float64. nil is 0.
+func toDividend(a any) (float64, error) {
+	if a == nil {
+		return 0, nil
+	}
+
+	f, err := values.Convert(a, float64Type)
+	if err != nil {
+		return 0, err
+	}
+
+	return f.(float64), nil
+}
+
+// isNumber returns a bool indicating whether v is an integer, a float, or a big number.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	default:
+		return values.IsBigNumber(v)
+	}
+}
+
+// bigOperand returns an operand of an arithmetic filter whose other operand is a
+// big number. An operand that isn't a number, such as nil or a string, is the
+// float that toFloat64 converts it to, as when neither operand is a big number.
+func bigOperand(v any) any {
+	if isNumber(v) {
+		return v
+	}
+
+	return toFloat64(v)
+}
+
+// promoteIntOverflow returns an arithmetic filter that applies filter to its
+// operands. If the PromoteIntOverflow option is set, an operation on two integers
+// whose result overflows an int64 returns a *big.Int, as Ruby does, instead of
+// wrapping around.
+func promoteIntOverflow(
+	filter func(a, b any) (any, error),
+	op func(z, x, y *big.Int) *big.Int,
+) func(fc expressions.FilterContext, a, b any) (any, error) {
+	return func(fc expressions.FilterContext, a, b any) (any, error) {
+		if !fc.Config().PromoteIntOverflow || !isIntegerType(a) || !isIntegerType(b) {
+			return filter(a, b)
+		}
+
+		n := op(new(big.Int), big.NewInt(toInt64(a)), big.NewInt(toInt64(b)))
+		if n.IsInt64() {
+			return n.Int64(), nil
+		}
+
+		return n, nil
+	}
+}
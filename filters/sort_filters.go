@@ -6,17 +6,18 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/osteele/liquid/expressions"
 	"github.com/osteele/liquid/values"
 )
 
-func sortFilter(array []any, key any) []any {
+func sortFilter(fc expressions.FilterContext, array []any, key any) []any {
 	result := make([]any, len(array))
 	copy(result, array)
 
 	if key == nil {
 		values.Sort(result)
 	} else {
-		values.SortByProperty(result, fmt.Sprint(key), true)
+		values.SortByPropertyValue(result, fmt.Sprint(key), true, fc.ValueOf)
 	}
 
 	return result
@@ -3,22 +3,21 @@ package filters
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"html"
 	"math"
+	"math/big"
 	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
-	"time"
 	"unicode"
 
+	"github.com/osteele/liquid/expressions"
 	"github.com/osteele/liquid/values"
-	"github.com/osteele/tuesday"
 )
 
-var errDivisionByZero = errors.New("division by zero")
+var errDivisionByZero = values.ErrDivisionByZero
 
 // A FilterDictionary holds filters.
 type FilterDictionary interface {
@@ -135,10 +134,10 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 		return append(append(result, a...), b...)
 	})
 	fd.AddFilter("join", joinFilter)
-	fd.AddFilter("map", func(a []any, key string) (result []any) {
+	fd.AddFilter("map", func(fc expressions.FilterContext, a []any, key string) (result []any) {
 		keyValue := values.ValueOf(key)
 		for _, obj := range a {
-			value := values.ValueOf(obj)
+			value := fc.ValueOf(obj)
 			result = append(result, value.PropertyValue(keyValue).Interface())
 		}
 
@@ -148,126 +147,81 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 	fd.AddFilter("sort", sortFilter)
 	// https://shopify.github.io/liquid/ does not demonstrate first and last as filters,
 	// but https://help.shopify.com/themes/liquid/filters/array-filters does
-	fd.AddFilter("first", func(a []any) any {
-		if len(a) == 0 {
-			return nil
+	fd.AddFilter("first", func(a any) (any, error) {
+		if r, ok := a.(values.Range); ok {
+			return r.First(), nil
 		}
 
-		return a[0]
+		return arrayFilter(a, func(a []any) any {
+			if len(a) == 0 {
+				return nil
+			}
+
+			return a[0]
+		})
 	})
-	fd.AddFilter("last", func(a []any) any {
-		if len(a) == 0 {
-			return nil
+	fd.AddFilter("last", func(a any) (any, error) {
+		if r, ok := a.(values.Range); ok {
+			return r.Last(), nil
 		}
 
-		return a[len(a)-1]
+		return arrayFilter(a, func(a []any) any {
+			if len(a) == 0 {
+				return nil
+			}
+
+			return a[len(a)-1]
+		})
 	})
 	fd.AddFilter("uniq", uniqFilter)
 
 	// date filters
-	fd.AddFilter("date", func(t time.Time, format func(string) string) (string, error) {
-		f := format("%a, %b %d, %y")
-		return tuesday.Strftime(f, t)
-	})
+	fd.AddFilter("date", dateFilter)
 
 	// number filters
-	fd.AddFilter("abs", math.Abs)
-	fd.AddFilter("ceil", func(a float64) int {
-		return int(math.Ceil(a))
-	})
-	fd.AddFilter("floor", func(a float64) int {
-		return int(math.Floor(a))
-	})
-	fd.AddFilter("modulo", math.Mod)
-	fd.AddFilter("minus", func(a, b any) any {
-		// If both operands are integers, perform integer arithmetic
-		if isIntegerType(a) && isIntegerType(b) {
-			return toInt64(a) - toInt64(b)
+	fd.AddFilter("abs", func(a any) (any, error) {
+		if values.IsBigNumber(a) {
+			return values.BigAbs(a), nil
 		}
-		// Otherwise, perform float arithmetic
-		return toFloat64(a) - toFloat64(b)
-	})
-	fd.AddFilter("plus", func(a, b any) any {
-		// If both operands are integers, perform integer arithmetic
-		if isIntegerType(a) && isIntegerType(b) {
-			return toInt64(a) + toInt64(b)
-		}
-		// Otherwise, perform float arithmetic
-		return toFloat64(a) + toFloat64(b)
+
+		return floatFilter(a, math.Abs)
 	})
-	fd.AddFilter("times", func(a, b any) any {
-		// If both operands are integers, perform integer arithmetic
-		if isIntegerType(a) && isIntegerType(b) {
-			return toInt64(a) * toInt64(b)
+	fd.AddFilter("ceil", func(a any) (any, error) {
+		if values.IsBigNumber(a) {
+			return values.BigCeil(a), nil
 		}
-		// Otherwise, perform float arithmetic
-		return toFloat64(a) * toFloat64(b)
-	})
-	fd.AddFilter("divided_by", func(a float64, b any) (any, error) {
-		divInt := func(a, b int64) (int64, error) {
-			if b == 0 {
-				return 0, errDivisionByZero
-			}
 
-			return a / b, nil
+		return floatFilter(a, func(a float64) int { return int(math.Ceil(a)) })
+	})
+	fd.AddFilter("floor", func(a any) (any, error) {
+		if values.IsBigNumber(a) {
+			return values.BigFloor(a), nil
 		}
 
-		divFloat := func(a, b float64) (float64, error) {
-			if b == 0 {
-				return 0, errDivisionByZero
-			}
-
-			return a / b, nil
-		}
-		switch q := b.(type) {
-		case int:
-			return divInt(int64(a), int64(q))
-		case int8:
-			return divInt(int64(a), int64(q))
-		case int16:
-			return divInt(int64(a), int64(q))
-		case int32:
-			return divInt(int64(a), int64(q))
-		case int64:
-			return divInt(int64(a), q)
-		case uint8:
-			return divInt(int64(a), int64(q))
-		case uint16:
-			return divInt(int64(a), int64(q))
-		case uint32:
-			return divInt(int64(a), int64(q))
-		case float32:
-			return divFloat(a, float64(q))
-		case float64:
-			return divFloat(a, q)
-		default:
-			return nil, fmt.Errorf("invalid divisor: '%v'", b)
-		}
+		return floatFilter(a, func(a float64) int { return int(math.Floor(a)) })
 	})
-	fd.AddFilter("round", func(n float64, places func(int) int) float64 {
+	fd.AddFilter("modulo", math.Mod)
+	fd.AddFilter("minus", promoteIntOverflow(minusFilter, (*big.Int).Sub))
+	fd.AddFilter("plus", promoteIntOverflow(plusFilter, (*big.Int).Add))
+	fd.AddFilter("times", promoteIntOverflow(timesFilter, (*big.Int).Mul))
+	fd.AddFilter("divided_by", dividedByFilter)
+	fd.AddFilter("round", func(n any, places func(int) int) (any, error) {
 		pl := places(0)
-		exp := math.Pow10(pl)
+		if values.IsBigNumber(n) {
+			return values.BigRound(n, pl), nil
+		}
 
-		return math.Floor(n*exp+0.5) / exp
+		return floatFilter(n, func(n float64) float64 {
+			exp := math.Pow10(pl)
+			return math.Floor(n*exp+0.5) / exp
+		})
 	})
 
-	// sequence filters
-	fd.AddFilter("size", values.Length)
-
 	// string filters
+	addCharacterFilters(fd)
 	fd.AddFilter("append", func(s, suffix string) string {
 		return s + suffix
 	})
-	fd.AddFilter("capitalize", func(s, suffix string) string {
-		if len(s) == 0 {
-			return s
-		}
-
-		return strings.ToUpper(s[:1]) + s[1:]
-	})
-	fd.AddFilter("downcase", func(s, suffix string) string {
-		return strings.ToLower(s)
-	})
 	fd.AddFilter("escape", html.EscapeString)
 	fd.AddFilter("escape_once", func(s, suffix string) string {
 		return html.EscapeString(html.UnescapeString(s))
@@ -289,56 +243,6 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 		return strings.Replace(s, old, n, 1)
 	})
 	fd.AddFilter("sort_natural", sortNaturalFilter)
-	fd.AddFilter("slice", func(v interface{}, start int, length func(int) int) interface{} {
-		// Are we in the []byte case? Transform []byte to string
-		if b, ok := v.([]byte); ok {
-			v = string(b)
-		}
-		// Are we in the string case?
-		if s, ok := v.(string); ok {
-			// Work on runes, not chars
-			runes := []rune(s)
-			n := length(1)
-			if start < 0 {
-				start = len(runes) + start
-				if start < 0 {
-					start = 0
-				}
-			}
-			if start > len(runes) {
-				start = len(runes)
-			}
-			end := start + n
-			if end > len(runes) {
-				end = len(runes)
-			}
-			return string(runes[start:end])
-		}
-		// Are we in the slice case?
-		// A type test cannot suffice because []T and []U are different types, so we must use conversion.
-		var slice []interface{}
-		if sliceIface, err := values.Convert(v, reflect.TypeOf(slice)); err == nil {
-			var ok bool
-			if slice, ok = sliceIface.([]interface{}); ok {
-				n := length(1)
-				if start < 0 {
-					start = len(slice) + start
-					if start < 0 {
-						start = 0
-					}
-				}
-				if start > len(slice) {
-					start = len(slice)
-				}
-				end := start + n
-				if end > len(slice) {
-					end = len(slice)
-				}
-				return slice[start:end]
-			}
-		}
-		return nil
-	})
 	fd.AddFilter("split", splitFilter)
 	fd.AddFilter("strip_html", func(s string) string {
 		// TODO this probably isn't sufficient
@@ -354,14 +258,6 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 	fd.AddFilter("rstrip", func(s string) string {
 		return strings.TrimRightFunc(s, unicode.IsSpace)
 	})
-	fd.AddFilter("truncate", func(s string, length func(int) int, ellipsis func(string) string) string {
-		n := length(50)
-		el := ellipsis("...")
-		// runes aren't bytes; don't use slice
-		re := regexp.MustCompile(fmt.Sprintf(`^(.{%d})..{%d,}`, n-len(el), len(el)))
-
-		return re.ReplaceAllString(s, `$1`+el)
-	})
 	fd.AddFilter("truncatewords", func(s string, length func(int) int, ellipsis func(string) string) string {
 		el := ellipsis("...")
 		n := length(15)
@@ -374,9 +270,6 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 
 		return m + el
 	})
-	fd.AddFilter("upcase", func(s, suffix string) string {
-		return strings.ToUpper(s)
-	})
 	fd.AddFilter("url_encode", url.QueryEscape)
 	fd.AddFilter("url_decode", url.QueryUnescape)
 
@@ -395,6 +288,21 @@ func AddStandardFilters(fd FilterDictionary) { //nolint: gocyclo
 	})
 }
 
+// arrayFilter applies fn to a, converted to an array. It is for filters that
+// handle some values, such as ranges, without converting them.
+func arrayFilter(a any, fn func([]any) any) (any, error) {
+	if a == nil {
+		return fn(nil), nil
+	}
+
+	array, err := values.Convert(a, reflect.TypeOf([]any{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(array.([]any)), nil
+}
+
 func joinFilter(a []any, sep func(string) string) any {
 	ss := make([]string, 0, len(a))
 	s := sep(" ")
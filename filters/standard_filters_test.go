@@ -2,6 +2,7 @@ package filters
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 	"testing"
 	"time"
@@ -224,6 +225,22 @@ Liquid" | slice: 2, 4`, "quid"},
 	{`20 | divided_by: 7`, int64(2)},
 	{`20 | divided_by: 7.0`, 2.857142857142857},
 
+	// an operand that isn't a number is 0.0, also when the other is a big number
+	{`big | plus: "2"`, 10.0},
+	{`"2" | plus: big`, 10.0},
+	{`big | plus: nil`, 10.0},
+	{`nil | plus: big`, 10.0},
+	{`big | minus: "2"`, 10.0},
+	{`"2" | minus: big`, -10.0},
+	{`big | minus: nil`, 10.0},
+	{`nil | minus: big`, -10.0},
+	{`big | times: "2"`, 0.0},
+	{`"2" | times: big`, 0.0},
+	{`big | times: nil`, 0.0},
+	{`nil | times: big`, 0.0},
+	{`"20" | divided_by: big`, 2.0},
+	{`nil | divided_by: big`, 0.0},
+
 	{`1.2 | round`, 1.0},
 	{`2.7 | round`, 3.0},
 	{`183.357 | round: 2`, 183.36},
@@ -241,6 +258,8 @@ var filterErrorTests = []struct {
 }{
 	{`20 | divided_by: 's'`, `error applying filter "divided_by" ("invalid divisor: 's'")`},
 	{`20 | divided_by: 0`, `error applying filter "divided_by" ("division by zero")`},
+	{`big | divided_by: "2"`, `error applying filter "divided_by" ("invalid divisor: '2'")`},
+	{`big | divided_by: nil`, `error applying filter "divided_by" ("invalid divisor: '<nil>'")`},
 }
 
 var filterTestBindings = map[string]any{
@@ -276,6 +295,7 @@ var filterTestBindings = map[string]any{
 	// Test uint types in arithmetic operations (issue #109)
 	"small_uint":   uint(1000),
 	"small_uint64": uint64(1000),
+	"big":          big.NewInt(10),
 
 	// for examples from liquid docs
 	"animals": []string{"zebra", "octopus", "giraffe", "Sally Snake"},
@@ -0,0 +1,147 @@
+package filters
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/values"
+)
+
+// addCharacterFilters defines the filters that count the characters of strings:
+// size, slice and truncate, and capitalize, which changes the case of the first
+// character. The configuration's CharacterMode determines what they count as a
+// character. It also defines upcase and downcase.
+//
+// As in Ruby, case mapping is full Unicode case mapping: "ß" upcases to "SS".
+func addCharacterFilters(fd FilterDictionary) {
+	fd.AddFilter("size", func(fc expressions.FilterContext, value any) int {
+		if s, ok := value.(string); ok {
+			return fc.Config().CharacterMode.Count(s)
+		}
+
+		return values.Length(value)
+	})
+	fd.AddFilter("slice", func(fc expressions.FilterContext, v any, start int, length func(int) int) any {
+		// Are we in the []byte case? Transform []byte to string
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		// Are we in the string case?
+		if s, ok := v.(string); ok {
+			chars := fc.Config().CharacterMode.Characters(s)
+			from, to := sliceBounds(len(chars), start, length(1))
+
+			return strings.Join(chars[from:to], "")
+		}
+		// Are we in the slice case?
+		// A type test cannot suffice because []T and []U are different types, so we must use conversion.
+		if slice, err := values.Convert(v, sliceType); err == nil {
+			if slice, ok := slice.([]any); ok {
+				from, to := sliceBounds(len(slice), start, length(1))
+				return slice[from:to]
+			}
+		}
+
+		return nil
+	})
+	fd.AddFilter("truncate", func(fc expressions.FilterContext, s string, length func(int) int, ellipsis func(string) string) string {
+		n := length(50)
+		el := ellipsis("...")
+		mode := fc.Config().CharacterMode
+
+		chars := mode.Characters(s)
+		if len(chars) <= n {
+			return s
+		}
+
+		// As in Ruby, the result is n characters long, including the ellipsis.
+		keep := max(0, n-mode.Count(el))
+
+		return strings.Join(chars[:keep], "") + el
+	})
+	fd.AddFilter("capitalize", func(fc expressions.FilterContext, s, suffix string) string {
+		if len(s) == 0 {
+			return s
+		}
+
+		first := fc.Config().CharacterMode.Characters(s)[0]
+
+		return titlecase(first) + downcase(s[len(first):])
+	})
+	fd.AddFilter("downcase", func(s, suffix string) string {
+		return downcase(s)
+	})
+	fd.AddFilter("upcase", func(s, suffix string) string {
+		return upcase(s)
+	})
+}
+
+var sliceType = reflect.TypeOf([]any{})
+
+// sliceBounds returns the bounds of the elements from start, counting from the
+// end if it is negative, of a sequence of length n. The bounds are clamped to
+// the sequence. A negative length selects no elements.
+func sliceBounds(n, start, length int) (from, to int) {
+	if start < 0 {
+		start = max(0, n+start)
+	}
+
+	from = min(start, n)
+	to = min(from+max(0, length), n)
+
+	return from, to
+}
+
+// The full case mappings that differ from the simple case mappings of the unicode
+// package, from the Unicode Character Database's SpecialCasing.txt. Only those
+// that aren't conditional on the language or context are included.
+var (
+	specialUpper = map[rune]string{
+		'ß': "SS", 'ŉ': "ʼN", 'ǰ': "J\u030c", 'ﬀ': "FF", 'ﬁ': "FI", 'ﬂ': "FL", 'ﬃ': "FFI", 'ﬄ': "FFL", 'ﬅ': "ST", 'ﬆ': "ST",
+	}
+	specialTitle = map[rune]string{
+		'ß': "Ss", 'ŉ': "ʼN", 'ǰ': "J\u030c", 'ﬀ': "Ff", 'ﬁ': "Fi", 'ﬂ': "Fl", 'ﬃ': "Ffi", 'ﬄ': "Ffl", 'ﬅ': "St", 'ﬆ': "St",
+	}
+	specialLower = map[rune]string{
+		'İ': "i\u0307",
+	}
+)
+
+func upcase(s string) string   { return mapCase(s, unicode.ToUpper, specialUpper) }
+func downcase(s string) string { return mapCase(s, unicode.ToLower, specialLower) }
+
+// titlecase returns s, with its first rune in title case, as for the first letter
+// of a word, and the rest of s unchanged.
+func titlecase(s string) string {
+	for i, r := range s {
+		if i > 0 {
+			break
+		}
+
+		if t, ok := specialTitle[r]; ok {
+			return t + s[len(string(r)):]
+		}
+
+		return string(unicode.ToTitle(r)) + s[len(string(r)):]
+	}
+
+	return s
+}
+
+func mapCase(s string, fn func(rune) rune, special map[rune]string) string {
+	var sb strings.Builder
+
+	sb.Grow(len(s))
+
+	for _, r := range s {
+		if m, ok := special[r]; ok {
+			sb.WriteString(m)
+		} else {
+			sb.WriteRune(fn(r))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/values"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharacterFilters(t *testing.T) {
+	bindings := map[string]any{
+		"accented": "cafe\u0301", // an e and a combining acute accent
+		"flags":    "\U0001F1EB\U0001F1F7\U0001F1E9\U0001F1EA",
+		"family":   "\U0001F468\u200d\U0001F469\u200d\U0001F467",
+		"empty":    "",
+		"crlf":     "a\r\nb",
+		"lines":    "line one\nline two",
+	}
+
+	// The runes column is what Ruby Liquid renders.
+	tests := []struct {
+		in               string
+		runes, graphemes any
+	}{
+		{`"héllo" | size`, 5, 5},
+		{`"héllo".size`, 5, 5},
+		{`"héllo".first`, "h", "h"},
+		{`"白鵬翔".last`, "翔", "翔"},
+		{`empty.first`, "", ""},
+		{`accented | size`, 5, 4},
+		{`accented.size`, 5, 4},
+		{`accented.last`, "\u0301", "e\u0301"},
+		{`flags.size`, 4, 2},
+		{`flags.first`, "\U0001F1EB", "\U0001F1EB\U0001F1F7"},
+		{`family | size`, 5, 1},
+		{`crlf | size`, 4, 3},
+
+		{`"héllo" | slice: 1, 2`, "él", "él"},
+		{`"héllo" | slice: -4, 2`, "él", "él"},
+		{`"héllo" | slice: 0, -1`, "", ""},
+		{`accented | slice: -1`, "\u0301", "e\u0301"},
+		{`accented | slice: 3, 2`, "e\u0301", "e\u0301"},
+		{`flags | slice: 1`, "\U0001F1F7", "\U0001F1E9\U0001F1EA"},
+
+		{`"héllo wörld" | truncate: 8`, "héllo...", "héllo..."},
+		{`"白鵬翔" | truncate: 2, ""`, "白鵬", "白鵬"},
+		{`"白鵬翔" | truncate: 2`, "...", "..."},
+		{`"白鵬翔" | truncate: 3`, "白鵬翔", "白鵬翔"},
+		{`lines | truncate: 10`, "line on...", "line on..."},
+		{`accented | truncate: 4, ""`, "cafe", "cafe\u0301"},
+		{`family | truncate: 1, ""`, "\U0001F468", "\U0001F468\u200d\U0001F469\u200d\U0001F467"},
+
+		{`"élan" | capitalize`, "Élan", "Élan"},
+		{`"ÉLAN VITAL" | capitalize`, "Élan vital", "Élan vital"},
+		{`"ßa" | capitalize`, "Ssa", "Ssa"},
+		{`"ǆemal" | capitalize`, "ǅemal", "ǅemal"},
+		{`"白鵬翔" | capitalize`, "白鵬翔", "白鵬翔"},
+
+		{`"Straße" | upcase`, "STRASSE", "STRASSE"},
+		{`"ﬁne" | upcase`, "FINE", "FINE"},
+		{`"ÉCOLE" | downcase`, "école", "école"},
+		{`"İ" | downcase`, "i\u0307", "i\u0307"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			for mode, expected := range map[values.CharacterMode]any{
+				values.RuneCharacters:     test.runes,
+				values.GraphemeCharacters: test.graphemes,
+			} {
+				cfg := expressions.NewConfig()
+				cfg.CharacterMode = mode
+				AddStandardFilters(&cfg)
+
+				actual, err := expressions.EvaluateString(test.in, expressions.NewContext(bindings, cfg))
+				require.NoError(t, err)
+				require.Equal(t, expected, actual, mode)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package format
+
+import (
+	"strings"
+)
+
+// Expression returns an expression, or the arguments of a tag such as for or cycle,
+// with normalized spacing: one space around | and comparison and assignment operators,
+// and after : and ,; and none inside parentheses and brackets, or around .. in ranges.
+// The content of string literals is unchanged.
+func Expression(s string) string {
+	var (
+		b    strings.Builder
+		prev string
+	)
+
+	for _, tok := range expressionTokens(s) {
+		if prev != "" && spaceBetween(prev, tok) {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(tok)
+		prev = tok
+	}
+
+	return b.String()
+}
+
+// operators are the punctuation tokens, longest first.
+var operators = []string{"==", "!=", "<>", ">=", "<=", "..", "|", ":", ",", "(", ")", "[", "]", "<", ">", "="}
+
+// expressionTokens splits an expression into string literals, operators and
+// punctuation, and the words between them.
+func expressionTokens(s string) []string {
+	var tokens []string
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				end = len(s)
+			} else {
+				end += i + 2
+			}
+
+			tokens = append(tokens, s[i:end])
+			i = end
+		case operatorAt(s, i) != "":
+			op := operatorAt(s, i)
+			tokens = append(tokens, op)
+			i += len(op)
+		default:
+			j := i + 1
+			for j < len(s) && !strings.ContainsRune(" \t\n\r\"'", rune(s[j])) && operatorAt(s, j) == "" {
+				j++
+			}
+
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
+
+func operatorAt(s string, i int) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s[i:], op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// spaceBetween returns true if there is a space between two adjacent tokens.
+func spaceBetween(prev, next string) bool {
+	switch {
+	case prev == "(" || prev == "[" || prev == "..":
+		return false
+	case next == ")" || next == "]" || next == ".." || next == ":" || next == ",":
+		return false
+	case next == "[" || strings.HasPrefix(next, "."):
+		// indexing or a property, unless it follows an operator or punctuation
+		return operatorAt(prev, 0) != "" && prev != ")" && prev != "]"
+	default:
+		return true
+	}
+}
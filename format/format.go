@@ -0,0 +1,203 @@
+// Package format formats Liquid template source in a canonical style.
+//
+// The formatter:
+//
+//   - puts one space inside {{ }} and {% %};
+//   - normalizes the spacing around filters, filter arguments and operators, in
+//     objects and in the standard tags whose arguments are expressions;
+//   - puts each statement of a {% liquid %} tag on its own line, and indents the
+//     bodies of its blocks; and
+//   - keeps whitespace control markers ({{- -}} and {%- -%}), and leaves text and
+//     the content of raw and comment tags unchanged.
+package format
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/osteele/liquid"
+	"github.com/osteele/liquid/parser"
+)
+
+// indent is the indentation of each level of a {% liquid %} tag.
+const indent = "  "
+
+// expressionTags are the standard tags whose arguments are expressions, or
+// sequences of expressions, whose spacing can be normalized.
+var expressionTags = map[string]bool{
+	"assign": true, "capture": true, "case": true, "cycle": true, "decrement": true,
+	"echo": true, "elsif": true, "for": true, "if": true, "include": true,
+	"increment": true, "render": true, "tablerow": true, "unless": true, "when": true,
+}
+
+// The block tags and their clauses, for indenting the statements of a {% liquid %} tag.
+var (
+	blockTags  = map[string]bool{"capture": true, "case": true, "comment": true, "for": true, "if": true, "tablerow": true, "unless": true}
+	clauseTags = map[string]bool{"else": true, "elsif": true, "when": true}
+)
+
+// Source formats template source that uses the standard delimiters. It returns an
+// error if the source can't be parsed.
+func Source(src []byte) ([]byte, error) {
+	root, err := liquid.NewEngine().ParseAST(src, "", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &formatter{}
+	f.node(root)
+
+	return f.buf.Bytes(), nil
+}
+
+type formatter struct {
+	buf bytes.Buffer
+}
+
+func (f *formatter) node(n parser.ASTNode) {
+	switch n := n.(type) {
+	case *parser.ASTSeq:
+		f.nodes(n.Children)
+	case *parser.ASTText:
+		f.buf.WriteString(n.Source)
+	case *parser.ASTObject:
+		f.delimited(n.Token, Expression(n.Args))
+	case *parser.ASTTag:
+		f.tag(n.Token)
+	case *parser.ASTBlock:
+		f.tag(n.Token)
+		f.nodes(n.Body)
+
+		for _, c := range n.Clauses {
+			f.node(c)
+		}
+
+		f.tag(n.End)
+	case *parser.ASTRaw:
+		f.tag(n.Token)
+		f.buf.WriteString(strings.Join(n.Slices, ""))
+		f.tag(n.End)
+	case *parser.ASTComment:
+		f.tag(n.Token)
+		f.buf.WriteString(strings.Join(n.Slices, ""))
+		f.tag(n.End)
+	case *parser.ASTTrim:
+		// The trim markers are written with the tags and objects.
+	}
+}
+
+func (f *formatter) nodes(nodes []parser.ASTNode) {
+	for _, n := range nodes {
+		f.node(n)
+	}
+}
+
+// tag writes a tag. It writes nothing for the zero Token, which is the end tag
+// of a clause, or of an unterminated raw or comment tag.
+func (f *formatter) tag(tok parser.Token) {
+	switch {
+	case tok.Source == "":
+	case tok.Name == "liquid" && tok.Args != "":
+		f.liquidTag(tok)
+	default:
+		f.delimited(tok, statement(tok.Name, tok.Args))
+	}
+}
+
+// delimited writes the content of a tag or object between its delimiters, with
+// the token's whitespace control markers.
+func (f *formatter) delimited(tok parser.Token, content string) {
+	f.buf.WriteString(tok.Source[:2])
+
+	if tok.Source[2] == '-' {
+		f.buf.WriteByte('-')
+	}
+
+	f.buf.WriteString(" " + content + " ")
+
+	if tok.Source[len(tok.Source)-3] == '-' {
+		f.buf.WriteByte('-')
+	}
+
+	f.buf.WriteString(tok.Source[len(tok.Source)-2:])
+}
+
+// liquidTag writes a {% liquid %} tag, with a statement on each line.
+func (f *formatter) liquidTag(tok parser.Token) {
+	var (
+		base      = f.lineIndent()
+		lines     []string
+		depth     = 1
+		inComment = false
+	)
+
+	for _, line := range strings.Split(tok.Args, "\n") {
+		stmt := strings.TrimSpace(line)
+		if stmt == "" {
+			continue
+		}
+
+		name, args, _ := strings.Cut(stmt, " ")
+		args = strings.TrimSpace(args)
+
+		switch {
+		case inComment && name != "endcomment":
+			// Comment lines are kept as they are.
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+			continue
+		case clauseTags[name]:
+			lines = append(lines, base+strings.Repeat(indent, max(depth-1, 0))+statement(name, args))
+			continue
+		case strings.HasPrefix(name, "end") && blockTags[name[3:]]:
+			depth = max(depth-1, 1)
+			inComment = false
+		}
+
+		lines = append(lines, base+strings.Repeat(indent, depth)+statement(name, args))
+
+		if blockTags[name] {
+			depth++
+			inComment = name == "comment"
+		}
+	}
+
+	f.buf.WriteString(tok.Source[:2])
+
+	if tok.Source[2] == '-' {
+		f.buf.WriteByte('-')
+	}
+
+	f.buf.WriteString(" liquid\n" + strings.Join(lines, "\n") + "\n" + base)
+
+	if tok.Source[len(tok.Source)-3] == '-' {
+		f.buf.WriteByte('-')
+	}
+
+	f.buf.WriteString(tok.Source[len(tok.Source)-2:])
+}
+
+// lineIndent returns the whitespace at the start of the current output line, if
+// the line is otherwise empty.
+func (f *formatter) lineIndent() string {
+	b := f.buf.Bytes()
+	line := b[bytes.LastIndexByte(b, '\n')+1:]
+
+	if len(bytes.Trim(line, " \t")) > 0 {
+		return ""
+	}
+
+	return string(line)
+}
+
+// statement returns a tag's name and arguments, with the spacing of the arguments
+// normalized if they are expressions.
+func statement(name, args string) string {
+	switch {
+	case args == "":
+		return name
+	case expressionTags[name]:
+		return name + " " + Expression(args)
+	default:
+		return name + " " + args
+	}
+}
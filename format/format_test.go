@@ -0,0 +1,63 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var formatTests = []struct{ in, expected string }{
+	// objects
+	{`{{x}}`, `{{ x }}`},
+	{`{{   x|upcase|append:"a",  'b'   }}`, `{{ x | upcase | append: "a", 'b' }}`},
+	{`{{-x-}} {{- x}} {{x -}}`, `{{- x -}} {{- x }} {{ x -}}`},
+	{`{{ a[0].b|default:"x  y" }}`, `{{ a[0].b | default: "x  y" }}`},
+	{`{{ (1 .. 5) | join:"," }}`, `{{ (1..5) | join: "," }}`},
+	{`{{ "a|b:c" }}`, `{{ "a|b:c" }}`},
+
+	// tags
+	{`{%if a==b and c>=1%}x{%else%}y{%endif%}`, `{% if a == b and c >= 1 %}x{% else %}y{% endif %}`},
+	{`{%- assign x=y|size -%}`, `{%- assign x = y | size -%}`},
+	{`{% for i in (1..n) reversed limit:2 %}{% endfor %}`, `{% for i in (1..n) reversed limit: 2 %}{% endfor %}`},
+	{`{%   cycle "a","b" %}`, `{% cycle "a", "b" %}`},
+	{`{% case x %}{%when 1,2%}a{% endcase %}`, `{% case x %}{% when 1, 2 %}a{% endcase %}`},
+	// the arguments of other tags are kept
+	{`{% custom a:b,c %}`, `{% custom a:b,c %}`},
+
+	// text, raw and comment content are preserved
+	{"  text {{x}}\n\t  more  ", "  text {{ x }}\n\t  more  "},
+	{`{%raw -%}{{x}} {%if%}{%- endraw%}`, `{% raw -%}{{x}} {%if%}{%- endraw %}`},
+	{`{%comment%} {{x|y}}  {% if %} {%endcomment-%}`, `{% comment %} {{x|y}}  {% if %} {% endcomment -%}`},
+
+	// liquid tags
+	{
+		"{% liquid\nassign x=1\nif x>0\necho x|plus:1\nelsif x\nfor i in (1..x)\necho i\nendfor\nelse\necho 'no'\nendif %}",
+		"{% liquid\n  assign x = 1\n  if x > 0\n    echo x | plus: 1\n  elsif x\n    for i in (1..x)\n      echo i\n    endfor\n  else\n    echo 'no'\n  endif\n%}",
+	},
+	{
+		"<div>\n  {%- liquid\n      case x\n  when 1\n echo 'one'\n    endcase\n    comment\n   keep  this\n endcomment -%}\n</div>",
+		"<div>\n  {%- liquid\n    case x\n    when 1\n      echo 'one'\n    endcase\n    comment\n   keep  this\n    endcomment\n  -%}\n</div>",
+	},
+}
+
+func TestSource(t *testing.T) {
+	for _, test := range formatTests {
+		out, err := Source([]byte(test.in))
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.expected, string(out), test.in)
+
+		// formatting is idempotent
+		again, err := Source(out)
+		require.NoError(t, err, test.in)
+		require.Equal(t, string(out), string(again), test.in)
+	}
+
+	_, err := Source([]byte(`{% if x %}`))
+	require.Error(t, err)
+}
+
+func TestExpression(t *testing.T) {
+	require.Equal(t, `product.price | times: 2 | round`, Expression(`product.price|times:2 |round`))
+	require.Equal(t, `x contains "a"`, Expression("x   contains\t\"a\""))
+	require.Equal(t, `a != b or c < d`, Expression(`a!=b  or c<d`))
+}
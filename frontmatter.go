@@ -0,0 +1,85 @@
+package liquid
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// removeFrontMatter removes the front matter from source, if the engine is configured
+// to, and source has front matter. It returns the parsed front matter, or nil; and the
+// rest of the source, and its location.
+func (e *Engine) removeFrontMatter(source []byte, loc parser.SourceLoc) (map[string]any, []byte, parser.SourceLoc, SourceError) {
+	if !e.frontMatter {
+		return nil, source, loc, nil
+	}
+
+	data, body, lines := splitFrontMatter(source)
+	if data == nil {
+		return nil, source, loc, nil
+	}
+
+	fm, err := parseFrontMatter(data, loc)
+	if err != nil {
+		return nil, nil, loc, err
+	}
+
+	loc.LineNo += lines
+
+	return fm, body, loc, nil
+}
+
+// splitFrontMatter splits a template source into its YAML front matter and its body.
+// Front matter is a block at the start of the source that begins and ends with a
+// "---" line, as in Jekyll; the end line can also be "...".
+//
+// It also returns the number of lines that precede the body. If the source doesn't
+// begin with front matter, it returns nil, source, and 0.
+func splitFrontMatter(source []byte) (frontMatter, body []byte, lines int) {
+	isDelimiter := func(line []byte, ends ...string) bool {
+		line = bytes.TrimRight(line, " \t\r")
+		for _, s := range ends {
+			if string(line) == s {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	i := bytes.IndexByte(source, '\n')
+	if i < 0 || !isDelimiter(source[:i], "---") {
+		return nil, source, 0
+	}
+
+	start := i + 1
+	for pos := start; pos < len(source); {
+		end := bytes.IndexByte(source[pos:], '\n')
+		if end < 0 {
+			end = len(source)
+		} else {
+			end += pos
+		}
+
+		if isDelimiter(source[pos:end], "---", "...") {
+			bodyStart := min(end+1, len(source))
+			return source[start:pos], source[bodyStart:], bytes.Count(source[:bodyStart], []byte{'\n'})
+		}
+
+		pos = end + 1
+	}
+
+	return nil, source, 0
+}
+
+// parseFrontMatter parses YAML front matter. loc is the location of the start of
+// the front matter, for error reporting.
+func parseFrontMatter(data []byte, loc parser.SourceLoc) (map[string]any, SourceError) {
+	m, err := DecodeYAMLBindings(data)
+	if err != nil {
+		return nil, parser.WrapError(fmt.Errorf("front matter: %w", err), parser.Token{SourceLoc: loc, Source: "---"})
+	}
+
+	return m, nil
+}
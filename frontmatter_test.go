@@ -0,0 +1,36 @@
+package liquid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var splitFrontMatterTests = []struct {
+	in, fm, body string
+	lines        int
+}{
+	{"---\na: 1\n---\nbody", "a: 1\n", "body", 3},
+	{"---\r\na: 1\r\n---\r\nbody", "a: 1\r\n", "body", 3},
+	{"---\na: 1\n...\nbody\n---\n", "a: 1\n", "body\n---\n", 3},
+	{"---\n---\nbody", "", "body", 2},
+	{"---\na: 1\n---", "a: 1\n", "", 2},
+	{"--- \na: 1\n---  \n", "a: 1\n", "", 3},
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	for i, test := range splitFrontMatterTests {
+		fm, body, lines := splitFrontMatter([]byte(test.in))
+		require.NotNil(t, fm, "%d", i)
+		require.Equal(t, test.fm, string(fm), "%d", i)
+		require.Equal(t, test.body, string(body), "%d", i)
+		require.Equal(t, test.lines, lines, "%d", i)
+	}
+
+	for _, src := range []string{"", "body", "---", "---\na: 1\n", "----\na: 1\n---\n", "text\n---\na: 1\n---\n"} {
+		fm, body, lines := splitFrontMatter([]byte(src))
+		require.Nil(t, fm, src)
+		require.Equal(t, src, string(body))
+		require.Zero(t, lines)
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/itchyny/gojq v0.12.17
 	github.com/osteele/tuesday v1.0.3
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/tools v0.24.0
 	gopkg.in/yaml.v2 v2.4.0
@@ -13,7 +14,6 @@ require (
 
 require (
 	github.com/itchyny/timefmt-go v0.1.6 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/mod v0.20.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
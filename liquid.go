@@ -8,8 +8,11 @@ The liquid package itself is versioned in gopkg.in. Subpackages have no compatib
 package liquid
 
 import (
+	"reflect"
+
 	"github.com/osteele/liquid/render"
 	"github.com/osteele/liquid/tags"
+	"github.com/osteele/liquid/values"
 )
 
 // Bindings is a map of variable names to values.
@@ -38,3 +41,42 @@ type SourceError interface {
 func IterationKeyedMap(m map[string]any) tags.IterationKeyedMap {
 	return m
 }
+
+// A FieldNamer returns the name by which templates access a struct field or method,
+// or "" to hide it. For a method, only the Name and Type of the field are set.
+// See Engine.SetFieldNamer.
+type FieldNamer = values.FieldNamer
+
+// ExactFieldNames names struct fields and methods by their Go names. This is the default.
+func ExactFieldNames(field reflect.StructField) string { return values.ExactFieldName(field) }
+
+// SnakeCaseFieldNames names struct fields and methods in snake_case: CompareAtPrice
+// as compare_at_price, and HTMLTitle as html_title. Adjacent initialisms aren't
+// separated: HTMLURL is htmlurl, unless a `liquid:"html_url"` tag names it.
+func SnakeCaseFieldNames(field reflect.StructField) string {
+	return values.SnakeCaseFieldName(field)
+}
+
+// CamelCaseFieldNames names struct fields and methods in camelCase: CompareAtPrice
+// as compareAtPrice, and HTMLTitle as htmlTitle.
+func CamelCaseFieldNames(field reflect.StructField) string {
+	return values.CamelCaseFieldName(field)
+}
+
+// JSONFieldNames names struct fields by the names in their `json:"name"` tags, and
+// fields without a name in the tag, and methods, by their Go names. A field tagged
+// `json:"-"` is hidden.
+func JSONFieldNames(field reflect.StructField) string { return values.JSONFieldName(field) }
+
+// A CharacterMode determines what templates count as a character of a string.
+// See Engine.SetCharacterMode.
+type CharacterMode = values.CharacterMode
+
+const (
+	// RuneCharacters counts Unicode code points, as Ruby does: "e\u0301", an e
+	// followed by a combining acute accent, has a size of 2. This is the default.
+	RuneCharacters = values.RuneCharacters
+	// GraphemeCharacters counts user-perceived characters: "e\u0301" and a flag
+	// emoji, which is a pair of regional indicators, each have a size of 1.
+	GraphemeCharacters = values.GraphemeCharacters
+)
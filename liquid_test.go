@@ -45,3 +45,54 @@ func ExampleIterationKeyedMap() {
 	// Output: a=1.
 	// a=1.
 }
+
+func TestRanges(t *testing.T) {
+	engine := NewEngine()
+	tests := []struct{ in, expected string }{
+		{`{{ (1..5) }} {{ (1..3) | append: "!" }}`, "1..5 1..3!"},
+		{`{% assign r = (1..5) %}{{ r.first }} {{ r.last }} {{ r.size }} {{ r | first }} {{ r | last }} {{ r | size }}`, "1 5 5 1 5 5"},
+		{`{% assign r = (1..5) %}{{ r | join: "," }} {{ r | reverse | join: "," }}`, "1,2,3,4,5 5,4,3,2,1"},
+		{`{% if (1..5) contains 3 %}a{% endif %}{% if (1..5) contains 6 %}b{% endif %}`, "a"},
+		{`{% assign n = 3 %}{% if (1..n) == (1..3) %}equal{% endif %}`, "equal"},
+		// properties and membership don't convert the range to an array
+		{`{% assign r = (1..1000000000000) %}{{ r.last }} {{ r | last }} {{ r.size }}{% if r contains 999 %} yes{% endif %}{% for i in r limit: 2 %} {{ i }}{% endfor %}`,
+			"1000000000000 1000000000000 1000000000000 yes 1 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := engine.ParseAndRenderString(test.in, nil)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out)
+		})
+	}
+}
+
+type playlist struct{ songs []string }
+
+func (p playlist) Len() int { return len(p.songs) }
+
+func TestPropertiesAndIndices(t *testing.T) {
+	engine := NewEngine()
+	bindings := Bindings{
+		"array":    []string{"a", "b", "c"},
+		"hash":     map[string]any{"title": "Home", "author": "Ann"},
+		"playlist": playlist{[]string{"x", "y"}},
+	}
+	tests := []struct{ in, expected string }{
+		{`{{ array[-1] }} {{ array[-3] }} {{ array[-4] }}`, "c a "},
+		{`{{ array[1.9] }} {{ array[-1.5] }} {{ array["1"] }}`, "b c "},
+		{`{{ "héllo".first }} {{ "héllo".last }} {{ "héllo".size }} [{{ "".first }}]`, "h o 5 []"},
+		{`{% assign pair = hash.first %}{{ pair[0] }}={{ pair[1] }} [{{ hash.last }}]`, "author=Ann []"},
+		{`{{ playlist.size }} {{ playlist | size }}`, "2 2"},
+		{`{{ array["size"] }}-{{ hash["size"] }}-{{ hash.size }}`, "--2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := engine.ParseAndRenderString(test.in, bindings)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out)
+		})
+	}
+}
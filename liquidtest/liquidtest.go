@@ -0,0 +1,243 @@
+// Package liquidtest runs golden-file tests of Liquid templates.
+//
+// A test case is a template file name.liquid, an optional data file name.json,
+// name.yaml or name.yml, and a file name.expected that holds the expected output.
+// The variables in the data file are bound when the template is rendered.
+// Templates and directories whose names begin with "_", such as the partials in an
+// _includes directory, aren't test cases; the cases can include them.
+//
+// A Go test can run the cases in a directory as subtests:
+//
+//	var update = flag.Bool("update", false, "update the golden files")
+//
+//	func TestTemplates(t *testing.T) {
+//		liquidtest.Test(t, "testdata", liquidtest.Options{Update: *update})
+//	}
+package liquidtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osteele/liquid"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// A Case is a golden-file test case.
+type Case struct {
+	Name     string // the template's path relative to the directory, without .liquid
+	Template string // the path of the template
+	Data     string // the path of the data file, or "" if there isn't one
+	Expected string // the path of the file with the expected output, which might not exist
+}
+
+// Options configure how test cases are run.
+type Options struct {
+	// Engine renders the templates. If it is nil, a new engine from liquid.NewEngine is used.
+	Engine *liquid.Engine
+	// Bindings are bound before the variables in the data file, which take precedence.
+	Bindings map[string]any
+	// Update causes the expected output files to be replaced by the actual output,
+	// instead of being compared with it.
+	Update bool
+}
+
+// A Result is the outcome of running a test case.
+type Result struct {
+	Case
+
+	Output  []byte
+	Err     error  // an error reading the case, or rendering the template
+	Diff    string // a unified diff from the expected to the actual output, if they differ
+	Updated bool   // whether the expected output file was written
+}
+
+// Passed returns true if the template rendered the expected output, or if the
+// expected output was updated.
+func (r Result) Passed() bool {
+	return r.Err == nil && r.Diff == ""
+}
+
+// Discover returns the test cases in the directory tree dir, in lexical order.
+// It skips templates and directories whose names begin with "_".
+func Discover(dir string) ([]Case, error) {
+	var cases []Case
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != dir && strings.HasPrefix(d.Name(), "_") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".liquid" {
+			return nil
+		}
+
+		base := strings.TrimSuffix(path, ".liquid")
+
+		name, err := filepath.Rel(dir, base)
+		if err != nil {
+			return err
+		}
+
+		c := Case{Name: filepath.ToSlash(name), Template: path, Expected: base + ".expected"}
+
+		for _, ext := range []string{".json", ".yaml", ".yml"} {
+			if _, err := os.Stat(base + ext); err == nil {
+				c.Data = base + ext
+				break
+			}
+		}
+
+		cases = append(cases, c)
+
+		return nil
+	})
+
+	return cases, err
+}
+
+// Run runs the test cases in the directory tree dir.
+func Run(dir string, opts Options) ([]Result, error) {
+	cases, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(cases))
+	for i, c := range cases {
+		results[i] = c.Run(opts)
+	}
+
+	return results, nil
+}
+
+// Test runs the test cases in the directory tree dir as subtests of t. A subtest
+// fails with a unified diff if the output differs from the expected output.
+func Test(t *testing.T, dir string, opts Options) {
+	t.Helper()
+
+	cases, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cases) == 0 {
+		t.Fatalf("no test cases in %s", dir)
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := c.Run(opts)
+
+			switch {
+			case r.Err != nil:
+				t.Error(r.Err)
+			case r.Diff != "":
+				t.Errorf("output differs from %s:\n%s", c.Expected, r.Diff)
+			}
+		})
+	}
+}
+
+// Run renders the case's template, and compares its output with the expected output,
+// or updates the expected output.
+func (c Case) Run(opts Options) Result {
+	r := Result{Case: c}
+
+	engine := opts.Engine
+	if engine == nil {
+		engine = liquid.NewEngine()
+	}
+
+	vars := liquid.Bindings{}
+	for k, v := range opts.Bindings {
+		vars[k] = v
+	}
+
+	if c.Data != "" {
+		data, err := liquid.LoadDataBindings(os.DirFS(filepath.Dir(c.Data)), filepath.Base(c.Data))
+		if err != nil {
+			r.Err = err
+			return r
+		}
+
+		for k, v := range data {
+			vars[k] = v
+		}
+	}
+
+	source, err := os.ReadFile(c.Template)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	tpl, serr := engine.ParseTemplateLocation(source, c.Template, 1)
+	if serr != nil {
+		r.Err = serr
+		return r
+	}
+
+	r.Output, serr = tpl.Render(vars)
+	if serr != nil {
+		r.Err = serr
+		return r
+	}
+
+	if opts.Update {
+		if expected, err := os.ReadFile(c.Expected); err != nil || !bytes.Equal(expected, r.Output) {
+			r.Err = os.WriteFile(c.Expected, r.Output, 0o644) // #nosec G306
+			r.Updated = r.Err == nil
+		}
+
+		return r
+	}
+
+	expected, err := os.ReadFile(c.Expected)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("%s doesn't exist; run with -update to create it", c.Expected)
+		}
+
+		r.Err = err
+
+		return r
+	}
+
+	r.Diff = Diff(c.Expected, "output", string(expected), string(r.Output))
+
+	return r
+}
+
+// Diff returns a unified diff from a to b, or "" if they are the same.
+func Diff(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: aName,
+		ToFile:   bName,
+		Context:  3,
+	})
+	if err != nil {
+		return err.Error()
+	}
+
+	return diff
+}
@@ -0,0 +1,73 @@
+package liquidtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osteele/liquid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTest(t *testing.T) {
+	Test(t, "testdata", Options{})
+}
+
+func TestDiscover(t *testing.T) {
+	cases, err := Discover("testdata")
+	require.NoError(t, err)
+	require.Equal(t, []Case{
+		{"hello", filepath.Join("testdata", "hello.liquid"), filepath.Join("testdata", "hello.json"), filepath.Join("testdata", "hello.expected")},
+		{"include", filepath.Join("testdata", "include.liquid"), filepath.Join("testdata", "include.yaml"), filepath.Join("testdata", "include.expected")},
+		{"nested/static", filepath.Join("testdata", "nested", "static.liquid"), "", filepath.Join("testdata", "nested", "static.expected")},
+		{"ordered", filepath.Join("testdata", "ordered.liquid"), filepath.Join("testdata", "ordered.yaml"), filepath.Join("testdata", "ordered.expected")},
+	}, cases)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	write("a.liquid", "{{ x | shout }}\nsame\n")
+	write("a.yaml", "x: hi\n")
+	write("a.expected", "hello\nsame\n")
+	write("b.liquid", "{{ x | nofilter }}")
+	write("c.liquid", "new")
+
+	engine := liquid.NewEngine()
+	engine.RegisterFilter("shout", func(s string) string { return s + "!" })
+
+	results, err := Run(dir, Options{Engine: engine})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.False(t, results[0].Passed())
+	require.NoError(t, results[0].Err)
+	require.Contains(t, results[0].Diff, "-hello\n+hi!\n same\n")
+	require.Contains(t, results[0].Diff, "+++ output")
+
+	require.False(t, results[1].Passed())
+	require.Contains(t, results[1].Err.Error(), "undefined filter")
+
+	require.False(t, results[2].Passed())
+	require.Contains(t, results[2].Err.Error(), "-update")
+
+	// update
+	results, err = Run(dir, Options{Engine: engine, Update: true, Bindings: map[string]any{"x": "unused"}})
+	require.NoError(t, err)
+	require.True(t, results[0].Passed())
+	require.True(t, results[0].Updated)
+	require.True(t, results[2].Updated)
+
+	b, err := os.ReadFile(filepath.Join(dir, "a.expected")) // #nosec G304
+	require.NoError(t, err)
+	require.Equal(t, "hi!\nsame\n", string(b))
+
+	results, err = Run(dir, Options{Engine: engine})
+	require.NoError(t, err)
+	require.True(t, results[0].Passed())
+	require.False(t, results[1].Passed())
+	require.True(t, results[2].Passed())
+}
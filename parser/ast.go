@@ -14,13 +14,24 @@ type ASTBlock struct {
 	syntax  BlockSyntax
 	Body    []ASTNode   // Body is the nodes before the first branch
 	Clauses []*ASTBlock // E.g. else and elseif w/in an if
+	End     Token       // End is the end tag, e.g. {% endif %}. It is the zero Token in a clause.
 }
 
 // ASTRaw holds the text between the start and end of a raw tag.
 type ASTRaw struct {
-	sourcelessNode
+	Token // the raw tag
 
 	Slices []string
+	End    Token // the endraw tag
+}
+
+// ASTComment is a {% comment %}…{% endcomment %} block. It isn't rendered, but
+// it's in the AST so that the source can be reconstructed from the AST.
+type ASTComment struct {
+	Token // the comment tag
+
+	Slices []string // the source between the tags
+	End    Token    // the endcomment tag
 }
 
 // ASTTag is a tag {% tag %} that is not a block start or end.
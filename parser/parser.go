@@ -31,6 +31,7 @@ func (c *Config) parseTokens(tokens []Token) (ASTNode, Error) { //nolint: gocycl
 		bn        *ASTBlock        // current block node
 		stack     []frame          // stack of blocks
 		rawTag    *ASTRaw          // current raw tag
+		comment   *ASTComment      // current comment tag
 		inComment = false
 		inRaw     = false
 	)
@@ -43,10 +44,14 @@ func (c *Config) parseTokens(tokens []Token) (ASTNode, Error) { //nolint: gocycl
 		case inComment:
 			if tok.Type == TagTokenType && tok.Name == "endcomment" {
 				inComment = false
+				comment.End = tok
+			} else {
+				comment.Slices = append(comment.Slices, tok.Source)
 			}
 		case inRaw:
 			if tok.Type == TagTokenType && tok.Name == "endraw" {
 				inRaw = false
+				rawTag.End = tok
 			} else {
 				rawTag.Slices = append(rawTag.Slices, tok.Source)
 			}
@@ -68,9 +73,11 @@ func (c *Config) parseTokens(tokens []Token) (ASTNode, Error) { //nolint: gocycl
 				switch {
 				case tok.Name == "comment":
 					inComment = true
+					comment = &ASTComment{Token: tok}
+					*ap = append(*ap, comment)
 				case tok.Name == "raw":
 					inRaw = true
-					rawTag = &ASTRaw{}
+					rawTag = &ASTRaw{Token: tok}
 					*ap = append(*ap, rawTag)
 				case cs.RequiresParent() && (sd == nil || !cs.CanHaveParent(sd)):
 					suffix := ""
@@ -93,6 +100,7 @@ func (c *Config) parseTokens(tokens []Token) (ASTNode, Error) { //nolint: gocycl
 					bn.Clauses = append(bn.Clauses, n)
 					ap = &n.Body
 				case cs.IsBlockEnd():
+					bn.End = tok
 					pop := func() {
 						f := stack[len(stack)-1]
 						stack = stack[:len(stack)-1]
@@ -72,3 +72,56 @@ func TestParser(t *testing.T) {
 		})
 	}
 }
+
+// astSource reconstructs the source of a node.
+func astSource(n ASTNode) string {
+	switch n := n.(type) {
+	case *ASTSeq:
+		s := ""
+		for _, c := range n.Children {
+			s += astSource(c)
+		}
+
+		return s
+	case *ASTBlock:
+		s := n.Source
+		for _, c := range n.Body {
+			s += astSource(c)
+		}
+
+		for _, c := range n.Clauses {
+			s += astSource(c)
+		}
+
+		return s + n.End.Source
+	case *ASTRaw:
+		return n.Source + strings.Join(n.Slices, "") + n.End.Source
+	case *ASTComment:
+		return n.Source + strings.Join(n.Slices, "") + n.End.Source
+	case *ASTTrim:
+		return ""
+	default:
+		return n.SourceText()
+	}
+}
+
+func TestParser_lossless(t *testing.T) {
+	cfg := Config{Grammar: grammarFake{}}
+	tests := append([]string{
+		"text {{ a -}} {%- if x -%} b {{- c }} {%- else %} d {% endif -%} e",
+		"{%- raw -%} {{ x }} {%- if %} {%- endraw -%}",
+		"a {%- comment %} {{ x }} {% if %} {%- endcomment -%} b",
+	}, func() (l []string) {
+		for _, test := range parserTests {
+			l = append(l, test.in)
+		}
+
+		return
+	}()...)
+
+	for _, src := range tests {
+		root, err := cfg.Parse(src, SourceLoc{})
+		require.NoError(t, err, src)
+		require.Equal(t, src, astSource(root))
+	}
+}
@@ -62,6 +62,12 @@ func Scan(data string, loc SourceLoc, delims []string) (tokens []Token) {
 				tok.Args = data[m[6]:m[7]]
 			}
 
+			// The arguments pattern matches the trim marker of a tag without arguments,
+			// as in {% else -%}.
+			if tok.Args == "-" && source[len(source)-3] == '-' {
+				tok.Args = ""
+			}
+
 			tokens = append(tokens, tok)
 			if source[len(source)-3] == '-' {
 				tokens = append(tokens, Token{
@@ -145,6 +145,16 @@ func TestScan_ws(t *testing.T) {
 				Type: TrimRightTokenType,
 			},
 		}},
+		{`{% tag -%}`, []Token{
+			{
+				Type:   TagTokenType,
+				Name:   "tag",
+				Source: "{% tag -%}",
+			},
+			{
+				Type: TrimRightTokenType,
+			},
+		}},
 	}
 	for i, test := range wsTests {
 		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
@@ -0,0 +1,82 @@
+package liquid
+
+import "math/big"
+
+// The interfaces in this file let a Go type behave as a Liquid value, without a
+// Drop that copies it into a map. A type can implement any of them. Liquid uses
+// its default behavior for the type's kind (struct, map, slice, etc.) for the
+// operations that the type doesn't implement, and for the properties and indices
+// that its methods report as missing.
+//
+// Unlike the values subpackage, these interfaces are part of the package's
+// compatibility guarantee.
+
+// A PropertyGetter returns the properties of the value, as in {{ product.title }}.
+//
+// LiquidProperty returns false if the value doesn't have the named property.
+// It is also used for string indices, as in {{ product["title"] }}, that an
+// Indexer doesn't handle.
+type PropertyGetter interface {
+	LiquidProperty(name string) (any, bool)
+}
+
+// An Indexer returns the elements of the value at an index, as in {{ list[0] }}.
+//
+// LiquidIndex returns false if the value doesn't have an element at the index.
+type Indexer interface {
+	LiquidIndex(index any) (any, bool)
+}
+
+// A Sizer reports the size of the value, for the size property and filter, and
+// for comparisons with empty.
+type Sizer interface {
+	LiquidSize() int
+}
+
+// An Iterable yields the elements of the value, for {% for %} loops, the first
+// and last properties, and filters that take arrays, such as join and sort.
+//
+// LiquidEach calls yield for each element, and stops if yield returns false.
+// It has the signature of an iter.Seq[any]. A loop reads the elements as it
+// iterates, so an Iterable can be a lazy sequence, such as a database cursor.
+// If the value is also a Sizer, forloop.length and forloop.rindex are available
+// in the loop.
+type Iterable interface {
+	LiquidEach(yield func(any) bool)
+}
+
+// A Comparer compares the value with another value, for the ==, !=, <, <=, >
+// and >= operators, and for sorting. The other value can be on either side of
+// the operator.
+//
+// LiquidCompare returns a negative number if the value is less than other, zero
+// if they are equal, and a positive number if it is greater. It returns false if
+// it can't compare the value with other; such values are unequal and unordered.
+type Comparer interface {
+	LiquidCompare(other any) (int, bool)
+}
+
+// Truthy reports whether the value is true in conditions, as in {% if user %}.
+// Without it, every value except nil and false is true.
+type Truthy interface {
+	LiquidTruthy() bool
+}
+
+// A Container implements the contains operator, as in {% if tags contains "sale" %}.
+type Container interface {
+	LiquidContains(value any) bool
+}
+
+// A Decimal is a number type, such as a fixed-point decimal from a third-party
+// package, that takes part in comparisons and in the arithmetic filters (plus,
+// minus, times, divided_by, round, ceil, floor and abs), as *big.Int, *big.Rat
+// and *big.Float values do.
+//
+// LiquidRat returns the exact value of the number. LiquidFromRat returns a
+// number of the same type with the value r; the arithmetic filters use it to
+// return their results, so that a price stays a Decimal. The output of a Decimal
+// is that of its String method, if it is a fmt.Stringer.
+type Decimal interface {
+	LiquidRat() *big.Rat
+	LiquidFromRat(r *big.Rat) any
+}
@@ -0,0 +1,159 @@
+package liquid
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A record is a row with named columns, such as an ORM might return.
+type record struct {
+	columns []string
+	values  []any
+}
+
+func (r *record) LiquidProperty(name string) (any, bool) {
+	for i, c := range r.columns {
+		if c == name {
+			return r.values[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func (r *record) LiquidIndex(index any) (any, bool) {
+	if i, ok := index.(int); ok && 0 <= i && i < len(r.values) {
+		return r.values[i], true
+	}
+
+	return nil, false
+}
+
+func (r *record) LiquidSize() int { return len(r.values) }
+
+func (r *record) LiquidEach(yield func(any) bool) {
+	for _, v := range r.values {
+		if !yield(v) {
+			return
+		}
+	}
+}
+
+func (r *record) LiquidContains(value any) bool {
+	name, ok := value.(string)
+	_, found := r.LiquidProperty(name)
+
+	return ok && found
+}
+
+// A version compares with other versions and with strings.
+type version struct{ Major, Minor int }
+
+func (v version) LiquidCompare(other any) (int, bool) {
+	var o version
+
+	switch other := other.(type) {
+	case version:
+		o = other
+	case string:
+		if _, err := fmt.Sscanf(other, "%d.%d", &o.Major, &o.Minor); err != nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if v.Major != o.Major {
+		return v.Major - o.Major, true
+	}
+
+	return v.Minor - o.Minor, true
+}
+
+func (v version) LiquidTruthy() bool { return v != version{} }
+
+func TestCustomValues(t *testing.T) {
+	engine := NewEngine()
+	bindings := map[string]any{
+		"row":      &record{[]string{"id", "title"}, []any{7, "Hat"}},
+		"empty":    &record{},
+		"v":        version{1, 2},
+		"zero":     version{},
+		"versions": []any{version{2, 0}, version{1, 10}, version{1, 2}},
+	}
+
+	tests := []struct{ in, expected string }{
+		// properties and indices
+		{`{{ row.title }} {{ row["title"] }} {{ row[0] }} {{ row.missing }}`, "Hat Hat 7"},
+		// size, first and last
+		{`{{ row.size }} {{ row | size }} {{ row.first }} {{ row.last }}`, "2 2 7 Hat"},
+		{`{% if empty == empty %}empty{% endif %}`, "empty"},
+		// iteration, and filters that take arrays
+		{`{% for x in row %}[{{ x }}]{% endfor %} {{ row | join: "," }}`, "[7][Hat] 7,Hat"},
+		// contains
+		{`{% if row contains "title" %}yes{% endif %}{% if row contains "x" %}no{% endif %}`, "yes"},
+		// comparison, on either side of the operator
+		{`{% if v == "1.2" %}a{% endif %}{% if "1.2" == v %}b{% endif %}{% if v < "1.10" %}c{% endif %}{% if "2.0" > v %}d{% endif %}`, "abcd"},
+		{`{% if v == 3 %}no{% else %}yes{% endif %}`, "yes"},
+		{`{% assign sorted = versions | sort %}{% for x in sorted %}{{ x.Major }}.{{ x.Minor }} {% endfor %}`, "1.2 1.10 2.0"},
+		// truthiness
+		{`{% if v %}a{% endif %}{% if zero %}b{% endif %}{% unless zero %}c{% endunless %}`, "ac"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := engine.ParseAndRenderString(test.in, bindings)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, strings.TrimSpace(out))
+		})
+	}
+}
+
+// money is a Decimal with two decimal places.
+type money int64
+
+func (m money) LiquidRat() *big.Rat { return big.NewRat(int64(m), 100) }
+
+func (m money) LiquidFromRat(r *big.Rat) any {
+	n := new(big.Rat).Mul(r, big.NewRat(100, 1))
+	return money(new(big.Int).Quo(n.Num(), n.Denom()).Int64())
+}
+
+func (m money) String() string { return "$" + m.LiquidRat().FloatString(2) }
+
+func TestBigNumbers(t *testing.T) {
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10)
+	engine := NewEngine()
+	bindings := map[string]any{
+		"huge":  huge,
+		"third": big.NewRat(1, 3),
+		"pi":    new(big.Float).SetPrec(200).SetFloat64(3.25),
+		"price": money(1999),
+	}
+
+	tests := []struct{ in, expected string }{
+		{`{{ huge }} {{ third }} {{ pi }} {{ price }}`, "100000000000000000000 1/3 3.25 $19.99"},
+		{`{{ huge | plus: 1 }} {{ huge | minus: huge }} {{ huge | times: 2 }} {{ huge | divided_by: 3 }}`,
+			"100000000000000000001 0 200000000000000000000 33333333333333333333"},
+		{`{{ third | plus: third }} {{ third | times: 3 }} {{ third | round: 2 }} {{ third | divided_by: 2 }}`, "2/3 1 33/100 1/6"},
+		{`{{ pi | times: 2 }} {{ pi | floor }} {{ pi | ceil }} {{ pi | round: 1 }}`, "6.5 3 4 3.3"},
+		{`{{ price | plus: 0.01 }} {{ price | times: 3 }} {{ price | divided_by: 2 }} {{ price | round }} {{ price | minus: 30 | abs }}`,
+			"$20.00 $59.97 $9.99 $20.00 $10.01"},
+		{`{% if huge > 9223372036854775807 %}a{% endif %}{% if third < 0.34 %}b{% endif %}{% if price == 19.99 %}c{% endif %}`, "abc"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := engine.ParseAndRenderString(test.in, bindings)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out)
+		})
+	}
+
+	_, err := engine.ParseAndRenderString(`{{ huge | divided_by: 0 }}`, bindings)
+	require.Error(t, err)
+}
@@ -98,6 +98,10 @@ func (c *Config) compileBlocks(blocks []*parser.ASTBlock) ([]*BlockNode, parser.
 func (c *Config) compileNodes(nodes []parser.ASTNode) ([]Node, parser.Error) {
 	out := make([]Node, 0, len(nodes))
 	for _, child := range nodes {
+		if _, ok := child.(*parser.ASTComment); ok {
+			continue
+		}
+
 		compiled, err := c.compileNode(child)
 		if err != nil {
 			return nil, err
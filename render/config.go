@@ -13,6 +13,9 @@ type Config struct {
 	Cache           map[string][]byte
 	StrictVariables bool
 	TemplateStore   TemplateStore
+	// IncludePaths are directories that the include tag searches, in order, for
+	// templates that aren't found relative to the including template.
+	IncludePaths []string
 
 	escapeReplacer Replacer
 
@@ -21,6 +24,13 @@ type Config struct {
 	// This is not part of the Shopify Liquid standard but is used in Jekyll and Gojekyll.
 	// Default: false (strict Shopify Liquid compatibility)
 	JekyllExtensions bool
+	// WarningHandler, if non-nil, is called with issues that don't cause rendering to fail,
+	// but are probably mistakes; for example, a comparison between a number and a string.
+	WarningHandler func(Warning)
+	// Tracer, if non-nil, observes the render.
+	Tracer Tracer
+	// Debugger, if non-nil, can pause the render before each node.
+	Debugger Debugger
 }
 
 type grammar struct {
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/osteele/liquid/parser"
 
@@ -68,6 +70,7 @@ type rendererContext struct {
 	ctx  nodeContext
 	node *TagNode
 	cn   *BlockNode
+	loc  parser.Locatable // the location of an object, if there's no tag or block
 }
 
 type invalidLocation struct{}
@@ -83,34 +86,34 @@ func (i invalidLocation) SourceText() string {
 var invalidLoc parser.Locatable = invalidLocation{}
 
 func (c rendererContext) Errorf(format string, a ...any) Error {
-	switch {
-	case c.node != nil:
-		return renderErrorf(c.node, format, a...)
-	case c.cn != nil:
-		return renderErrorf(c.cn, format, a...)
-	default:
-		return renderErrorf(invalidLoc, format, a...)
-	}
+	return renderErrorf(c.location(), format, a...)
 }
 
 func (c rendererContext) WrapError(err error) Error {
+	return wrapRenderError(err, c.location())
+}
+
+// location returns the current tag or block, for error and warning reporting.
+func (c rendererContext) location() parser.Locatable {
 	switch {
 	case c.node != nil:
-		return wrapRenderError(err, c.node)
+		return c.node
 	case c.cn != nil:
-		return wrapRenderError(err, c.cn)
+		return c.cn
+	case c.loc != nil:
+		return c.loc
 	default:
-		return wrapRenderError(err, invalidLoc)
+		return invalidLoc
 	}
 }
 
 func (c rendererContext) Evaluate(expr expressions.Expression) (out any, err error) {
-	return c.ctx.Evaluate(expr)
+	return c.ctx.Evaluate(expr, c.location())
 }
 
 // EvaluateString evaluates an expression within the template context.
 func (c rendererContext) EvaluateString(source string) (out any, err error) {
-	return expressions.EvaluateString(source, expressions.NewContext(c.ctx.bindings, c.ctx.config.Config.Config))
+	return expressions.EvaluateString(source, c.ctx.expressionContext(c.location()))
 }
 
 // Bindings returns the current lexical environment.
@@ -133,7 +136,7 @@ func (c rendererContext) ExpandTagArg() (string, error) {
 
 		buf := new(bytes.Buffer)
 
-		err = Render(root, buf, c.ctx.bindings, c.ctx.config)
+		err = Render(root, buf, c.ctx.bindings, *c.ctx.config)
 		if err != nil {
 			return "", err
 		}
@@ -159,40 +162,96 @@ func (c rendererContext) RenderChildren(w io.Writer) Error {
 }
 
 func (c rendererContext) RenderFile(filename string, b map[string]any) (string, error) {
-	source, err := c.ctx.config.TemplateStore.ReadTemplate(filename)
-	if err != nil && os.IsNotExist(err) {
-		// Is it cached?
-		if cval, ok := c.ctx.config.Cache[filename]; ok {
-			source = cval
-		} else {
-			return "", err
-		}
-	} else if err != nil {
-		return "", err
-	}
-
-	root, err := c.ctx.config.Compile(string(source), c.node.SourceLoc)
+	root, err := c.compileFile(filename)
 	if err != nil {
 		return "", err
 	}
 
-	bindings := map[string]any{}
-	for k, v := range c.ctx.bindings {
-		bindings[k] = v
-	}
-
+	ctx := newNodeContext(c.ctx.bindings, *c.ctx.config)
 	for k, v := range b {
-		bindings[k] = v
+		ctx.bindings[k] = v
 	}
 
 	buf := new(bytes.Buffer)
-	if err := Render(root, buf, bindings, c.ctx.config); err != nil {
+	tw := &trimWriter{w: buf}
+	ctx.mapping = includeMapping(filename, c.node.SourceLoc)
+	ctx.depth = c.ctx.depth
+
+	if c.ctx.sourceMap != nil {
+		ctx.sourceMap = &sourceMapper{mapping: ctx.mapping}
+		tw.sm = ctx.sourceMap
+	}
+
+	if err := renderRoot(root, tw, ctx); err != nil {
 		return "", err
 	}
 
+	if sm := c.ctx.sourceMap; sm != nil {
+		sm.include = &renderedInclude{buf.String(), ctx.sourceMap.segments}
+	}
+
 	return buf.String(), nil
 }
 
+// compileFile loads and compiles an included template, and reports this to the tracer.
+func (c rendererContext) compileFile(filename string) (root Node, err error) {
+	var (
+		start                 = time.Now()
+		loadTime, compileTime time.Duration
+	)
+
+	if t := c.ctx.config.Tracer; t != nil {
+		defer func() {
+			t.Include(IncludeEvent{c.node.SourceLoc, c.node.Source, filename, loadTime, compileTime, err})
+		}()
+	}
+
+	source, err := c.readTemplate(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	loadTime = time.Since(start)
+	start = time.Now()
+
+	root, err = c.ctx.config.Compile(string(source), c.node.SourceLoc)
+	compileTime = time.Since(start)
+
+	return root, err
+}
+
+// readTemplate reads an included template from the template store, or from the
+// cache, or from the include paths.
+func (c rendererContext) readTemplate(filename string) ([]byte, error) {
+	config := c.ctx.config
+
+	source, err := config.TemplateStore.ReadTemplate(filename)
+	if err == nil || !os.IsNotExist(err) {
+		return source, err
+	}
+
+	// Is it cached?
+	if cval, ok := config.Cache[filename]; ok {
+		return cval, nil
+	}
+
+	// The include tag resolves names relative to the including template.
+	// Search the include paths for the same name.
+	rel, relErr := filepath.Rel(filepath.Dir(c.SourceFile()), filename)
+	if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, err
+	}
+
+	for _, dir := range config.IncludePaths {
+		s, e := config.TemplateStore.ReadTemplate(filepath.Join(dir, rel))
+		if e == nil || !os.IsNotExist(e) {
+			return s, e
+		}
+	}
+
+	return nil, err
+}
+
 // InnerString renders the children to a string.
 func (c rendererContext) InnerString() (string, error) {
 	buf := new(bytes.Buffer)
@@ -259,6 +318,8 @@ func (c rendererContext) SourceFile() string {
 		return c.node.SourceLoc.Pathname
 	case c.cn != nil:
 		return c.cn.SourceLoc.Pathname
+	case c.loc != nil:
+		return c.loc.SourceLocation().Pathname
 	default:
 		return ""
 	}
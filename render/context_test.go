@@ -155,3 +155,23 @@ func TestContext_file_not_found_error(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, os.IsNotExist(err.Cause()))
 }
+
+func TestContext_IncludePaths(t *testing.T) {
+	cfg := NewConfig()
+	addContextTestTags(cfg)
+	cfg.IncludePaths = []string{"missing_dir", "testdata"}
+	root, err := cfg.Compile(`{% test_render_file render_file.txt %}`, parser.SourceLoc{Pathname: "page.html", LineNo: 1})
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = Render(root, buf, contextTestBindings, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "rendered shadowed=2", buf.String())
+
+	// names outside the including template's directory aren't searched for
+	root, err = cfg.Compile(`{% test_render_file ../render_file.txt %}`, parser.SourceLoc{Pathname: "page.html", LineNo: 1})
+	require.NoError(t, err)
+	err = Render(root, io.Discard, contextTestBindings, cfg)
+	require.Error(t, err)
+	require.True(t, os.IsNotExist(err.Cause()))
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/parser"
+)
+
+// A Debugger is called before each template node that has a source location is
+// rendered. The render is paused until it returns, so a debugger can stop at
+// breakpoints, or step through the nodes one at a time.
+type Debugger interface {
+	// Break is called before a node is rendered. If it returns an error, the
+	// render stops, and returns that error.
+	Break(s DebugState) error
+}
+
+// A DebugState is the state of a render that is paused before a node.
+type DebugState struct {
+	// SourceLoc is the location of the node. Unlike Node.SourceLocation, this is
+	// in the node's own file if the node is in an included template.
+	parser.SourceLoc
+
+	Node Node
+	// Depth is the number of nodes that enclose this one, including the include
+	// tags that included its template. It can be used to step over or out of a node.
+	Depth int
+
+	ctx nodeContext
+}
+
+// Bindings returns the variable bindings, including forloop inside a for loop.
+// Changes to the map change the bindings of the rest of the render.
+func (s DebugState) Bindings() map[string]any {
+	return s.ctx.bindings
+}
+
+// Evaluate evaluates a Liquid expression, such as "product.price | times: 2",
+// in the current bindings.
+func (s DebugState) Evaluate(source string) (any, error) {
+	return expressions.EvaluateString(source, s.ctx.expressionContext(s.Node))
+}
+
+// debugNode calls the debugger with the state before n is rendered.
+func debugNode(d Debugger, n Node, ctx nodeContext) Error {
+	s := DebugState{ctx.mapping.apply(n.SourceLocation()), n, ctx.depth, ctx}
+	if err := d.Break(s); err != nil {
+		return wrapRenderError(err, n)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/osteele/liquid/parser"
+	"github.com/stretchr/testify/require"
+)
+
+type debuggerFunc func(DebugState) error
+
+func (f debuggerFunc) Break(s DebugState) error { return f(s) }
+
+func TestRender_debugger(t *testing.T) {
+	var stops []string
+
+	cfg := NewConfig()
+	addContextTestTags(cfg)
+	addRenderTestTags(cfg)
+	cfg.Debugger = debuggerFunc(func(s DebugState) error {
+		value, err := s.Evaluate("shadowed | plus: 1")
+		stops = append(stops, fmt.Sprintf("%s %d %q %v %v", s.SourceLoc, s.Depth, s.Node.SourceText(), value, err))
+
+		return nil
+	})
+	cfg.AddFilter("plus", func(a, b int) int { return a + b })
+
+	src := "{% if true %}{{ x }}{% endif %}\n{% test_render_file testdata/render_file.txt %}"
+	root, err := cfg.Compile(src, parser.SourceLoc{Pathname: "page.html", LineNo: 1})
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, Render(root, buf, map[string]any{"x": 123, "shadowed": 1}, cfg))
+	require.Equal(t, "123\nrendered shadowed=2", buf.String())
+	require.Equal(t, []string{
+		`page.html:1 0 "{% if true %}" 2 <nil>`,
+		`page.html:1 1 "{{ x }}" 2 <nil>`,
+		`page.html:1 0 "\n" 2 <nil>`,
+		`page.html:2 0 "{% test_render_file testdata/render_file.txt %}" 2 <nil>`,
+		`testdata/render_file.txt:1 1 "rendered shadowed=" 3 <nil>`,
+		`testdata/render_file.txt:1 1 "{{ shadowed }}" 3 <nil>`,
+	}, stops)
+
+	// the debugger can stop the render
+	errStop := errors.New("stopped")
+	cfg.Debugger = debuggerFunc(func(s DebugState) error {
+		if s.Depth > 0 {
+			return errStop
+		}
+
+		return nil
+	})
+	err = Render(root, io.Discard, nil, cfg)
+	require.Error(t, err)
+	require.Equal(t, errStop, err.Cause())
+	require.Equal(t, 1, err.LineNumber())
+}
@@ -2,6 +2,7 @@ package render
 
 import (
 	"github.com/osteele/liquid/expressions"
+	"github.com/osteele/liquid/parser"
 )
 
 // nodeContext provides the evaluation context for rendering the AST.
@@ -9,8 +10,39 @@ import (
 // This type has a clumsy name so that render.Context, in the public API, can
 // have a clean name that doesn't stutter.
 type nodeContext struct {
-	bindings map[string]any
-	config   Config
+	bindings  map[string]any
+	config    *Config
+	sourceMap *sourceMapper // non-nil if the render is recording a source map
+	// the location mapping of the nodes, if they are from an included template
+	mapping locationMapping
+	// the number of nodes with source locations that enclose the current one, for the debugger
+	depth int
+	// resolves context drops; it's made once per context, so that evaluations don't allocate it
+	resolveDrop func(value, source any) (any, bool)
+}
+
+// A locationMapping maps the source locations of the nodes of an included template
+// to the included file. An included template is compiled at the location of the
+// include tag, so that its errors are reported there, but it's more useful to
+// associate its output with its own lines.
+type locationMapping struct {
+	pathname  string // if this is empty, locations are unchanged
+	lineDelta int
+}
+
+// includeMapping returns the mapping for a template that is included from filename,
+// by the include tag at loc.
+func includeMapping(filename string, loc parser.SourceLoc) locationMapping {
+	return locationMapping{filename, 1 - loc.LineNo}
+}
+
+func (m locationMapping) apply(loc parser.SourceLoc) parser.SourceLoc {
+	if m.pathname != "" {
+		loc.Pathname = m.pathname
+		loc.LineNo += m.lineDelta
+	}
+
+	return loc
 }
 
 // newNodeContext creates a new evaluation context.
@@ -22,10 +54,55 @@ func newNodeContext(scope map[string]any, c Config) nodeContext {
 		vars[k] = v
 	}
 
-	return nodeContext{vars, c}
+	ctx := &nodeContext{bindings: vars, config: &c}
+	ctx.resolveDrop = func(value, source any) (any, bool) {
+		if d, ok := value.(contextDrop); ok {
+			return d.ToLiquidContext(ctx.contextAt(source)), true
+		}
+
+		return nil, false
+	}
+
+	return *ctx
 }
 
 // Evaluate evaluates an expression within the template context.
-func (c nodeContext) Evaluate(expr expressions.Expression) (out any, err error) {
-	return expr.Evaluate(expressions.NewContext(c.bindings, c.config.Config.Config))
+// Warnings are reported at the source location of loc.
+func (c nodeContext) Evaluate(expr expressions.Expression, loc parser.Locatable) (out any, err error) {
+	return expr.Evaluate(c.expressionContext(loc))
+}
+
+// expressionContext returns an expression evaluation context, that reports
+// warnings and trace events at the source location of loc.
+func (c nodeContext) expressionContext(loc parser.Locatable) expressions.Context {
+	cfg := c.config.Config.Config
+	cfg.WarningHandler = c.config.warningHandlerFor(loc)
+	cfg.FilterHook = c.config.filterHookFor(loc)
+	cfg.DropHook = c.config.dropHookFor(loc)
+	cfg.ResolveDrop = c.resolveDrop
+	cfg.DropSource = loc
+
+	return expressions.NewContext(c.bindings, cfg)
+}
+
+// contextAt returns the render context of the tag, block or object at loc, which
+// is the source of an expression that reads a context drop.
+func (c nodeContext) contextAt(loc any) rendererContext {
+	rc := rendererContext{ctx: c}
+
+	switch loc := loc.(type) {
+	case *TagNode:
+		rc.node = loc
+	case *BlockNode:
+		rc.cn = loc
+	case parser.Locatable:
+		rc.loc = loc
+	}
+
+	return rc
+}
+
+// A contextDrop is a value that implements the liquid package's ContextDrop interface.
+type contextDrop interface {
+	ToLiquidContext(Context) any
 }
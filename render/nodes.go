@@ -62,6 +62,16 @@ type TrimNode struct {
 	parser.TrimDirection
 }
 
+// hasSource returns a bool indicating whether n has a source location and text.
+func hasSource(n Node) bool {
+	switch n.(type) {
+	case *BlockNode, *ObjectNode, *TagNode, *TextNode:
+		return true
+	default:
+		return false
+	}
+}
+
 // FIXME requiring this is a bad design
 type sourcelessNode struct{}
 
@@ -0,0 +1,371 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// A Profile records where a render spent its time, and where it allocated memory.
+type Profile struct {
+	ProfileStats // the totals for the render
+
+	// Nodes are the profiles of the template's top-level nodes. Each rendering
+	// of a node, for example in each iteration of a loop, has its own profile.
+	Nodes []*ProfileNode
+
+	filters  map[string]*ProfileStats
+	includes map[string]*ProfileStats
+	memory   bool // whether the stats of nodes, filters and includes count allocations
+}
+
+// ProfileOptions configure a profiled render.
+type ProfileOptions struct {
+	// Memory records the allocations of each node, filter and included template,
+	// as well as those of the whole render. It reads the memory allocator
+	// statistics before and after each of them, which stops the world each time,
+	// and slows the render by an order of magnitude or more.
+	Memory bool
+}
+
+// ProfileStats are the costs of a template node, line, filter or included template.
+// The allocations are zero, except for the totals of a render, unless the render
+// was profiled with ProfileOptions.Memory.
+type ProfileStats struct {
+	Calls      int
+	Time       time.Duration
+	Allocs     uint64 // the number of heap allocations
+	AllocBytes uint64 // the number of bytes allocated
+}
+
+// A ProfileNode is the profile of one rendering of a template node.
+// Its stats include those of its children.
+type ProfileNode struct {
+	parser.SourceLoc
+	ProfileStats
+
+	Source   string // the source text of the node
+	Include  string // the name of the template that the node included, if any
+	Children []*ProfileNode
+}
+
+// A HotSpot is the aggregate profile of a template line, filter, or included template.
+type HotSpot struct {
+	ProfileStats
+
+	Name string // the source location, filter name, or template filename
+}
+
+// RenderProfiled is the same as Render, except that it also profiles the render.
+//
+// Profiling slows rendering, since it reads the clock before and after each node
+// and filter. The allocations of the whole render are read once, at its start and
+// end; those of each node are only read if opts.Memory is set, which costs much
+// more. The cost of the profiler is excluded from the profile, as far as possible.
+func RenderProfiled(node Node, w io.Writer, vars map[string]any, c Config, opts ProfileOptions) (*Profile, Error) {
+	p := &profiler{
+		next: c.Tracer,
+		profile: Profile{
+			filters:  map[string]*ProfileStats{},
+			includes: map[string]*ProfileStats{},
+			memory:   opts.Memory,
+		},
+	}
+	c.Tracer = p
+
+	start := p.readMemStats()
+	if err := Render(node, w, vars, c); err != nil {
+		return nil, err
+	}
+
+	p.profile.ProfileStats = p.readMemStats().since(start)
+	p.profile.Calls = 1
+
+	return &p.profile, nil
+}
+
+// Lines returns the aggregate profiles of the template lines, in decreasing order of time.
+// The stats of a line don't include those of nested lines, such as the body of a block
+// tag, or the lines of an included template.
+func (p *Profile) Lines() []HotSpot {
+	lines := map[string]*ProfileStats{}
+
+	var walk func([]*ProfileNode)
+	walk = func(nodes []*ProfileNode) {
+		for _, n := range nodes {
+			self := n.ProfileStats
+			for _, c := range n.Children {
+				self.sub(c.ProfileStats)
+			}
+
+			key := n.SourceLoc.String()
+			if lines[key] == nil {
+				lines[key] = &ProfileStats{}
+			}
+
+			lines[key].add(self)
+			walk(n.Children)
+		}
+	}
+	walk(p.Nodes)
+
+	return hotSpots(lines)
+}
+
+// Filters returns the aggregate profiles of the filters, in decreasing order of time.
+func (p *Profile) Filters() []HotSpot { return hotSpots(p.filters) }
+
+// Includes returns the aggregate profiles of the included templates, in decreasing order of time.
+// The stats of an included template include loading, compiling and rendering it.
+func (p *Profile) Includes() []HotSpot { return hotSpots(p.includes) }
+
+// WriteReport writes a report of the hot spots to w. It lists at most limit
+// lines, filters, and included templates, or all of them if limit is zero.
+// Their allocations are listed if the render was profiled with ProfileOptions.Memory.
+func (p *Profile) WriteReport(w io.Writer, limit int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Total: %v, %d allocations (%d bytes)\n", p.Time, p.Allocs, p.AllocBytes) //nolint:errcheck
+
+	for _, section := range []struct {
+		title string
+		spots []HotSpot
+	}{
+		{"LINE", p.Lines()},
+		{"FILTER", p.Filters()},
+		{"INCLUDE", p.Includes()},
+	} {
+		if len(section.spots) == 0 {
+			continue
+		}
+
+		if limit > 0 && len(section.spots) > limit {
+			section.spots = section.spots[:limit]
+		}
+
+		fmt.Fprintf(tw, "\n%s\tCALLS\tTIME\t%%", section.title) //nolint:errcheck
+
+		if p.memory {
+			fmt.Fprint(tw, "\tALLOCS\tBYTES") //nolint:errcheck
+		}
+
+		fmt.Fprintln(tw) //nolint:errcheck
+
+		for _, s := range section.spots {
+			percent := 0.0
+			if p.Time > 0 {
+				percent = 100 * float64(s.Time) / float64(p.Time)
+			}
+
+			fmt.Fprintf(tw, "%s\t%d\t%v\t%.1f", s.Name, s.Calls, s.Time, percent) //nolint:errcheck
+
+			if p.memory {
+				fmt.Fprintf(tw, "\t%d\t%d", s.Allocs, s.AllocBytes) //nolint:errcheck
+			}
+
+			fmt.Fprintln(tw) //nolint:errcheck
+		}
+	}
+
+	return tw.Flush()
+}
+
+func hotSpots(m map[string]*ProfileStats) []HotSpot {
+	spots := make([]HotSpot, 0, len(m))
+	for name, stats := range m {
+		spots = append(spots, HotSpot{*stats, name})
+	}
+
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].Time != spots[j].Time {
+			return spots[i].Time > spots[j].Time
+		}
+
+		return spots[i].Name < spots[j].Name
+	})
+
+	return spots
+}
+
+func (s *ProfileStats) add(o ProfileStats) {
+	s.Calls += o.Calls
+	s.Time += o.Time
+	s.Allocs += o.Allocs
+	s.AllocBytes += o.AllocBytes
+}
+
+func (s *ProfileStats) sub(o ProfileStats) {
+	s.Time -= o.Time
+	s.Allocs -= o.Allocs
+	s.AllocBytes -= o.AllocBytes
+}
+
+// A profileSample is a reading of the clock and, if memory is profiled, the memory
+// allocator statistics.
+type profileSample struct {
+	time          time.Time
+	allocs, bytes uint64
+}
+
+func (s profileSample) since(start profileSample) ProfileStats {
+	return ProfileStats{
+		Time:       s.time.Sub(start.time),
+		Allocs:     s.allocs - start.allocs,
+		AllocBytes: s.bytes - start.bytes,
+	}
+}
+
+// A profiler is a Tracer that builds a Profile. It forwards events to next, if
+// that is non-nil.
+type profiler struct {
+	next    Tracer
+	profile Profile
+	stack   []*profileFrame
+	filters []profileSample // the start of each filter application in progress
+	mem     runtime.MemStats
+}
+
+// A profileFrame is a node that is being rendered.
+type profileFrame struct {
+	node  *ProfileNode
+	start profileSample
+	// the cost of the profiler's bookkeeping while the node was rendered
+	overhead ProfileStats
+	// the mapping of the source locations of the node's children
+	mapping locationMapping
+}
+
+// sample reads the clock, and the memory allocator statistics if memory is profiled.
+func (p *profiler) sample() profileSample {
+	if !p.profile.memory {
+		return profileSample{time: time.Now()}
+	}
+
+	return p.readMemStats()
+}
+
+// readMemStats reads the clock and the memory allocator statistics. This stops the world.
+func (p *profiler) readMemStats() profileSample {
+	runtime.ReadMemStats(&p.mem)
+	return profileSample{time.Now(), p.mem.Mallocs, p.mem.TotalAlloc}
+}
+
+func (p *profiler) top() *profileFrame {
+	if len(p.stack) == 0 {
+		return nil
+	}
+
+	return p.stack[len(p.stack)-1]
+}
+
+// exclude attributes the cost since start to the profiler, instead of to the node that is being rendered.
+func (p *profiler) exclude(start profileSample) {
+	if top := p.top(); top != nil {
+		top.overhead.add(p.sample().since(start))
+	}
+}
+
+func (p *profiler) EnterNode(n Node) {
+	if p.next != nil {
+		p.next.EnterNode(n)
+	}
+
+	start := p.sample()
+	node := &ProfileNode{SourceLoc: n.SourceLocation(), Source: n.SourceText()}
+	frame := &profileFrame{node: node}
+
+	if parent := p.top(); parent != nil {
+		parent.node.Children = append(parent.node.Children, node)
+		node.SourceLoc = parent.mapping.apply(node.SourceLoc)
+		frame.mapping = parent.mapping
+	} else {
+		p.profile.Nodes = append(p.profile.Nodes, node)
+	}
+
+	p.exclude(start)
+	p.stack = append(p.stack, frame)
+	frame.start = p.sample()
+}
+
+func (p *profiler) ExitNode(n Node, elapsed time.Duration, err error) {
+	end := p.sample()
+	frame := p.top()
+	p.stack = p.stack[:len(p.stack)-1]
+
+	node := frame.node
+	node.ProfileStats = end.since(frame.start)
+	node.sub(frame.overhead)
+	node.Calls = 1
+
+	if node.Include != "" {
+		if p.profile.includes[node.Include] == nil {
+			p.profile.includes[node.Include] = &ProfileStats{}
+		}
+
+		p.profile.includes[node.Include].add(node.ProfileStats)
+	}
+
+	if parent := p.top(); parent != nil {
+		parent.overhead.add(frame.overhead)
+	}
+
+	p.exclude(end)
+
+	if p.next != nil {
+		p.next.ExitNode(n, elapsed, err)
+	}
+}
+
+func (p *profiler) EnterFilter(e FilterEvent) {
+	if p.next != nil {
+		p.next.EnterFilter(e)
+	}
+
+	start := p.sample()
+	p.filters = append(p.filters, profileSample{})
+	p.exclude(start)
+	p.filters[len(p.filters)-1] = p.sample()
+}
+
+func (p *profiler) ExitFilter(e FilterEvent) {
+	end := p.sample()
+	start := p.filters[len(p.filters)-1]
+	p.filters = p.filters[:len(p.filters)-1]
+
+	stats := end.since(start)
+	stats.Calls = 1
+
+	if p.profile.filters[e.Name] == nil {
+		p.profile.filters[e.Name] = &ProfileStats{}
+	}
+
+	p.profile.filters[e.Name].add(stats)
+	p.exclude(end)
+
+	if p.next != nil {
+		p.next.ExitFilter(e)
+	}
+}
+
+func (p *profiler) Include(e IncludeEvent) {
+	// The included template was compiled at the location of the include tag.
+	// Attribute its nodes to their own file instead.
+	if top := p.top(); top != nil {
+		top.node.Include = e.Filename
+		top.mapping = includeMapping(e.Filename, e.SourceLoc)
+	}
+
+	if p.next != nil {
+		p.next.Include(e)
+	}
+}
+
+func (p *profiler) ResolveDrop(e DropEvent) {
+	if p.next != nil {
+		p.next.ResolveDrop(e)
+	}
+}
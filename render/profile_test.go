@@ -0,0 +1,77 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/osteele/liquid/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderProfiled(t *testing.T) {
+	cfg := NewConfig()
+	addContextTestTags(cfg)
+	addRenderTestTags(cfg)
+	cfg.AddFilter("repeat", func(s string, n int) string { return strings.Repeat(s, n) })
+
+	src := "{% if true %}\n{{ 'ab' | repeat: 100 }}{{ 'ab' | repeat: 100 }}\n{% endif %}\n{% test_render_file testdata/render_file.txt %}"
+	root, err := cfg.Compile(src, parser.SourceLoc{Pathname: "page.html", LineNo: 1})
+	require.NoError(t, err)
+
+	profile, err := RenderProfiled(root, io.Discard, map[string]any{"shadowed": 1}, cfg, ProfileOptions{Memory: true})
+	require.NoError(t, err)
+	require.Len(t, profile.Nodes, 3)
+
+	block := profile.Nodes[0]
+	require.Equal(t, "{% if true %}", block.Source)
+	require.Len(t, block.Children, 4)
+	require.Equal(t, 2, block.Children[1].LineNo)
+	require.Greater(t, block.Children[1].AllocBytes, uint64(200))
+
+	include := profile.Nodes[2]
+	require.Equal(t, "testdata/render_file.txt", include.Include)
+	require.Len(t, include.Children, 2)
+	require.Equal(t, parser.SourceLoc{Pathname: "testdata/render_file.txt", LineNo: 1}, include.Children[1].SourceLoc)
+
+	lines := map[string]int{}
+	for _, s := range profile.Lines() {
+		lines[s.Name] = s.Calls
+	}
+
+	require.Equal(t, map[string]int{"page.html:1": 2, "page.html:2": 3, "page.html:3": 1, "page.html:4": 1, "testdata/render_file.txt:1": 2}, lines)
+
+	filters := profile.Filters()
+	require.Len(t, filters, 1)
+	require.Equal(t, "repeat", filters[0].Name)
+	require.Equal(t, 2, filters[0].Calls)
+	require.Greater(t, filters[0].AllocBytes, uint64(400))
+
+	includes := profile.Includes()
+	require.Len(t, includes, 1)
+	require.Equal(t, HotSpot{include.ProfileStats, "testdata/render_file.txt"}, includes[0])
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, profile.WriteReport(buf, 2))
+	report := buf.String()
+	require.Contains(t, report, "Total: ")
+	require.Contains(t, report, "LINE")
+	require.Contains(t, report, "FILTER")
+	require.Contains(t, report, "INCLUDE")
+	require.Contains(t, report, "repeat")
+	require.Contains(t, report, "ALLOCS")
+	require.Equal(t, 1+4+3+3, strings.Count(report, "\n"), report)
+
+	// without Memory, only the totals count allocations
+	profile, err = RenderProfiled(root, io.Discard, map[string]any{"shadowed": 1}, cfg, ProfileOptions{})
+	require.NoError(t, err)
+	require.Greater(t, profile.AllocBytes, uint64(400))
+	require.Zero(t, profile.Nodes[0].Children[1].AllocBytes)
+	require.Zero(t, profile.Filters()[0].AllocBytes)
+
+	buf.Reset()
+	require.NoError(t, profile.WriteReport(buf, 2))
+	require.Contains(t, buf.String(), "allocations")
+	require.NotContains(t, buf.String(), "ALLOCS")
+}
@@ -15,9 +15,25 @@ import (
 
 // Render renders the render tree.
 func Render(node Node, w io.Writer, vars map[string]any, c Config) Error {
-	tw := trimWriter{w: w}
+	return renderRoot(node, &trimWriter{w: w}, newNodeContext(vars, c))
+}
+
+// RenderWithSourceMap is the same as Render, except that it also records which
+// template node produced each range of the output.
+func RenderWithSourceMap(node Node, w io.Writer, vars map[string]any, c Config) (*SourceMap, Error) {
+	sm := &sourceMapper{}
+	ctx := newNodeContext(vars, c)
+	ctx.sourceMap = sm
+
+	if err := renderRoot(node, &trimWriter{w: w, sm: sm}, ctx); err != nil {
+		return nil, err
+	}
+
+	return &SourceMap{Segments: sm.segments}, nil
+}
 
-	err := node.render(&tw, newNodeContext(vars, c))
+func renderRoot(node Node, tw *trimWriter, ctx nodeContext) Error {
+	err := renderNode(node, tw, ctx)
 	if err != nil {
 		return err
 	}
@@ -29,6 +45,28 @@ func Render(node Node, w io.Writer, vars map[string]any, c Config) Error {
 	return nil
 }
 
+// renderNode renders a node. Use this instead of calling n.render directly,
+// so that the render context can observe the node.
+func renderNode(n Node, w *trimWriter, ctx nodeContext) Error {
+	if d := ctx.config.Debugger; d != nil && hasSource(n) {
+		if err := debugNode(d, n, ctx); err != nil {
+			return err
+		}
+
+		ctx.depth++
+	}
+
+	if ctx.sourceMap != nil {
+		defer ctx.sourceMap.enter(n)()
+	}
+
+	if t := ctx.config.Tracer; t != nil && hasSource(n) {
+		return traceNode(t, n, w, ctx)
+	}
+
+	return n.render(w, ctx)
+}
+
 // RenderSequence renders a sequence of nodes.
 func (c nodeContext) RenderSequence(w io.Writer, seq []Node) Error {
 	tw, ok := w.(*trimWriter)
@@ -37,7 +75,7 @@ func (c nodeContext) RenderSequence(w io.Writer, seq []Node) Error {
 	}
 
 	for _, n := range seq {
-		err := n.render(tw, c)
+		err := renderNode(n, tw, c)
 		if err != nil {
 			return err
 		}
@@ -62,7 +100,7 @@ func (n *BlockNode) render(w *trimWriter, ctx nodeContext) Error {
 		panic(fmt.Errorf("unset renderer for %v", n))
 	}
 
-	err := renderer(w, rendererContext{ctx, nil, n})
+	err := renderer(w, rendererContext{ctx: ctx, cn: n})
 
 	return wrapRenderError(err, n)
 }
@@ -79,7 +117,7 @@ func (n *RawNode) render(w *trimWriter, ctx nodeContext) Error {
 }
 
 func (n *ObjectNode) render(w *trimWriter, ctx nodeContext) Error {
-	value, err := ctx.Evaluate(n.expr)
+	value, err := ctx.Evaluate(n.expr, n)
 	if err != nil {
 		return wrapRenderError(err, n)
 	}
@@ -110,7 +148,7 @@ func (n *ObjectNode) render(w *trimWriter, ctx nodeContext) Error {
 
 func (n *SeqNode) render(w *trimWriter, ctx nodeContext) Error {
 	for _, c := range n.Children {
-		err := c.render(w, ctx)
+		err := renderNode(c, w, ctx)
 		if err != nil {
 			return err
 		}
@@ -120,7 +158,7 @@ func (n *SeqNode) render(w *trimWriter, ctx nodeContext) Error {
 }
 
 func (n *TagNode) render(w *trimWriter, ctx nodeContext) Error {
-	err := wrapRenderError(n.renderer(w, rendererContext{ctx, n, nil}), n)
+	err := wrapRenderError(n.renderer(w, rendererContext{ctx: ctx, node: n}), n)
 	return err
 }
 
@@ -156,6 +194,11 @@ func writeObject(w io.Writer, value any) error {
 		// for instances of error and *string
 	}
 
+	if s, ok := values.FormatBigNumber(value); ok {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+
 	rt := reflect.ValueOf(value)
 	switch rt.Kind() {
 	case reflect.Array, reflect.Slice:
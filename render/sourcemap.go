@@ -0,0 +1,272 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// A SourceMap records which template node produced each range of rendered output.
+//
+// Output that was produced by a node inside an included template is mapped to
+// that node, not to the include tag.
+type SourceMap struct {
+	// Segments are in output order, and don't overlap. Output that isn't covered
+	// by a segment, such as the text of a raw tag, has no recorded source.
+	Segments []SourceMapSegment
+}
+
+// A SourceMapSegment records that the output bytes [Start, End) were produced by a template node.
+type SourceMapSegment struct {
+	parser.SourceLoc
+
+	Start, End int
+	Source     string // the source text of the node
+	// Verbatim is true if the output is a copy of the node's source text, as for
+	// template text outside of {{ }} and {% %}. The output lines of a verbatim
+	// segment correspond to successive source lines.
+	Verbatim bool
+}
+
+type v3SourceMap struct {
+	Version  int      `json:"version"`
+	File     string   `json:"file,omitempty"`
+	Sources  []string `json:"sources"`
+	Names    []string `json:"names"`
+	Mappings string   `json:"mappings"`
+}
+
+// MarshalV3 returns the JSON encoding of the map in the Source Map Revision 3 format,
+// for output that was rendered to file.
+//
+// Generated columns count UTF-16 code units, as in JavaScript. Template source
+// locations don't record columns, so every mapping is to column 0 of its source line.
+func (m *SourceMap) MarshalV3(file string, output []byte) ([]byte, error) {
+	var (
+		sources  []string
+		indices  = map[string]int{}
+		mappings strings.Builder
+		// the previous values of the fields that are delta-encoded across lines
+		prevSource, prevLine int
+		// the position in the output
+		pos, col int
+		// whether the current line already has a mapping
+		lineHasMapping bool
+	)
+
+	emit := func(fields ...int) {
+		if lineHasMapping {
+			mappings.WriteByte(',')
+		}
+
+		lineHasMapping = true
+
+		for _, f := range fields {
+			writeVLQ(&mappings, f)
+		}
+	}
+
+	// advance moves pos to end, updating the generated column. At each line
+	// break before end, it calls newline.
+	advance := func(end int, newline func()) {
+		for pos < end {
+			r, size := utf8.DecodeRune(output[pos:])
+
+			pos += size
+			if r == '\n' {
+				mappings.WriteByte(';')
+
+				col, lineHasMapping = 0, false
+
+				if pos < end && newline != nil {
+					newline()
+				}
+			} else {
+				col += utf16.RuneLen(r)
+			}
+		}
+	}
+
+	prevCol := 0
+	mapping := func(source, line int) {
+		if !lineHasMapping {
+			prevCol = 0
+		}
+
+		emit(col-prevCol, source-prevSource, line-prevLine, 0)
+		prevCol, prevSource, prevLine = col, source, line
+	}
+
+	for i, seg := range m.Segments {
+		if seg.Start > len(output) || seg.End > len(output) {
+			break
+		}
+
+		advance(seg.Start, nil)
+
+		index, ok := indices[seg.Pathname]
+		if !ok {
+			index = len(sources)
+			indices[seg.Pathname] = index
+			sources = append(sources, seg.Pathname)
+		}
+
+		line := max(seg.LineNo-1, 0)
+		mapping(index, line)
+		advance(seg.End, func() {
+			if seg.Verbatim {
+				line++
+			}
+
+			mapping(index, line)
+		})
+
+		// mark the end of the segment, unless another segment starts here
+		if seg.End < len(output) && (i+1 == len(m.Segments) || m.Segments[i+1].Start > seg.End) && output[seg.End-1] != '\n' {
+			if !lineHasMapping {
+				prevCol = 0
+			}
+
+			emit(col - prevCol)
+			prevCol = col
+		}
+	}
+
+	advance(len(output), nil)
+
+	return json.Marshal(v3SourceMap{
+		Version:  3,
+		File:     file,
+		Sources:  append([]string{}, sources...),
+		Names:    []string{},
+		Mappings: mappings.String(),
+	})
+}
+
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// writeVLQ writes the Base64 VLQ encoding of n.
+func writeVLQ(sb *strings.Builder, n int) {
+	v := n << 1
+	if n < 0 {
+		v = (-n << 1) | 1
+	}
+
+	for {
+		digit := v & 0x1f
+
+		v >>= 5
+		if v > 0 {
+			digit |= 0x20
+		}
+
+		sb.WriteByte(base64Digits[digit])
+
+		if v == 0 {
+			return
+		}
+	}
+}
+
+// A sourceMapper records the sources of a render's output.
+//
+// The trimWriter buffers output so that it can be trimmed, so the sourceMapper
+// keeps the segments for the buffered output separately, with offsets relative
+// to the start of the buffer, until the buffer is flushed.
+type sourceMapper struct {
+	segments []SourceMapSegment // segments that have been written, with output offsets
+	offset   int                // the number of bytes written to the output
+	buffered []SourceMapSegment // segments in the trimWriter buffer, with buffer offsets
+	node     Node               // the node that is being rendered
+	include  *renderedInclude   // the most recent include that the current node rendered
+	mapping  locationMapping    // the mapping of the nodes' source locations
+}
+
+// A renderedInclude is the output of an included template, and its source map.
+type renderedInclude struct {
+	output   string
+	segments []SourceMapSegment
+}
+
+// enter records that n is being rendered. It returns a function that restores the previous state.
+func (sm *sourceMapper) enter(n Node) func() {
+	if !hasSource(n) {
+		// sourceless nodes are attributed to their parent
+		return func() {}
+	}
+
+	prev := sm.node
+	sm.node = n
+
+	return func() {
+		sm.node = prev
+		sm.include = nil
+	}
+}
+
+// buffer records that data is being written at offset into the trimWriter buffer, after
+// trimming its first skip bytes.
+func (sm *sourceMapper) buffer(data []byte, skip, offset int) {
+	if inc := sm.include; inc != nil && inc.output == string(data) {
+		sm.include = nil
+
+		for _, seg := range inc.segments {
+			seg.Start, seg.End = max(seg.Start-skip, 0)+offset, seg.End-skip+offset
+			if seg.End > seg.Start {
+				sm.add(seg)
+			}
+		}
+
+		return
+	}
+
+	if sm.node == nil || skip == len(data) {
+		return
+	}
+
+	seg := SourceMapSegment{
+		SourceLoc: sm.mapping.apply(sm.node.SourceLocation()),
+		Start:     offset,
+		End:       offset + len(data) - skip,
+		Source:    sm.node.SourceText(),
+	}
+	if _, ok := sm.node.(*TextNode); ok {
+		seg.Verbatim = true
+		seg.LineNo += strings.Count(string(data[:skip]), "\n")
+	}
+
+	sm.add(seg)
+}
+
+// add adds a segment to the buffered segments, merging it with the previous
+// segment if they are adjacent and from the same node.
+func (sm *sourceMapper) add(seg SourceMapSegment) {
+	if n := len(sm.buffered); n > 0 {
+		prev := &sm.buffered[n-1]
+		if prev.End == seg.Start && prev.SourceLoc == seg.SourceLoc && prev.Source == seg.Source && !seg.Verbatim {
+			prev.End = seg.End
+			return
+		}
+	}
+
+	sm.buffered = append(sm.buffered, seg)
+}
+
+// flush records that the first n bytes of the trimWriter buffer have been written
+// to the output, and that the rest of the buffer has been discarded.
+func (sm *sourceMapper) flush(n int) {
+	for _, seg := range sm.buffered {
+		seg.End = min(seg.End, n)
+		if seg.Start < seg.End {
+			seg.Start += sm.offset
+			seg.End += sm.offset
+			sm.segments = append(sm.segments, seg)
+		}
+	}
+
+	sm.buffered = sm.buffered[:0]
+	sm.offset += n
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/osteele/liquid/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWithSourceMap(t *testing.T) {
+	cfg := NewConfig()
+	addRenderTestTags(cfg)
+
+	src := "a\n{{ int }}\n{%- y %} {% if true %}b{% y -%}\n\n c{% endif %}"
+	root, err := cfg.Compile(src, parser.SourceLoc{Pathname: "test.html", LineNo: 1})
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	sm, err := RenderWithSourceMap(root, buf, renderTestBindings, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "a\n123y byc", buf.String())
+
+	type seg struct {
+		out, source string
+		line        int
+	}
+
+	var segs []seg
+	for _, s := range sm.Segments {
+		segs = append(segs, seg{buf.String()[s.Start:s.End], s.Source, s.LineNo})
+	}
+
+	require.Equal(t, []seg{
+		{"a\n", "a\n", 1},
+		{"123", "{{ int }}", 2},
+		{"y", "{%- y %}", 3},
+		{" ", " ", 3},
+		{"b", "b", 3},
+		{"y", "{% y -%}", 3},
+		{"c", "\n\n c", 5},
+	}, segs)
+}
+
+func TestSourceMap_MarshalV3(t *testing.T) {
+	sm := SourceMap{Segments: []SourceMapSegment{
+		{SourceLoc: parser.SourceLoc{Pathname: "a.html", LineNo: 1}, Start: 0, End: 4, Verbatim: true},
+		{SourceLoc: parser.SourceLoc{Pathname: "b.html", LineNo: 3}, Start: 4, End: 7},
+		{SourceLoc: parser.SourceLoc{Pathname: "a.html", LineNo: 2}, Start: 8, End: 9},
+	}}
+	output := []byte("x\nyzé\nw!v")
+
+	data, err := sm.MarshalV3("out.html", output)
+	require.NoError(t, err)
+
+	var v3 map[string]any
+	require.NoError(t, json.Unmarshal(data, &v3))
+	require.Equal(t, float64(3), v3["version"])
+	require.Equal(t, "out.html", v3["file"])
+	require.Equal(t, []any{"a.html", "b.html"}, v3["sources"])
+	// line 1: col 0 → a.html:1
+	// line 2: col 0 → a.html:2 (verbatim); col 2 → b.html:3
+	// line 3: col 0 unmapped; col 1 → a.html:2; col 2 unmapped
+	require.Equal(t, "AAAA;AACA,ECCA;CDDA,C", v3["mappings"])
+}
+
+func TestWriteVLQ(t *testing.T) {
+	encode := func(ns ...int) string {
+		var sb strings.Builder
+		for _, n := range ns {
+			writeVLQ(&sb, n)
+		}
+
+		return sb.String()
+	}
+	require.Equal(t, "A", encode(0))
+	require.Equal(t, "C", encode(1))
+	require.Equal(t, "D", encode(-1))
+	require.Equal(t, "gB", encode(16))
+	require.Equal(t, "2HwcqxB", encode(123, 456, 789))
+}
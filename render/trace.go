@@ -0,0 +1,113 @@
+package render
+
+import (
+	"time"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// A Tracer observes a render. It can be used to build metrics or tracing spans.
+//
+// Its methods are called synchronously, from the goroutine that is rendering.
+// Embed NopTracer to implement only some of them.
+type Tracer interface {
+	// EnterNode is called before a node that has a source location is rendered.
+	EnterNode(n Node)
+	// ExitNode is called after a node that EnterNode was called with has been rendered.
+	ExitNode(n Node, elapsed time.Duration, err error)
+	// EnterFilter is called before each filter application. The event's Elapsed
+	// and Err aren't set yet.
+	EnterFilter(e FilterEvent)
+	// ExitFilter is called after a filter application that EnterFilter was called with.
+	ExitFilter(e FilterEvent)
+	// Include is called after an included template has been loaded and compiled.
+	Include(e IncludeEvent)
+	// ResolveDrop is called after a drop has been converted to a Liquid value.
+	ResolveDrop(e DropEvent)
+}
+
+// A FilterEvent describes a filter application.
+type FilterEvent struct {
+	parser.SourceLoc
+
+	Source  string // the source text of the template node
+	Name    string // the filter name
+	Args    []any  // the filter input, followed by its arguments
+	Elapsed time.Duration
+	Err     error
+}
+
+// An IncludeEvent describes the loading and compilation of an included template.
+type IncludeEvent struct {
+	parser.SourceLoc
+
+	Source      string // the source text of the include tag
+	Filename    string
+	LoadTime    time.Duration
+	CompileTime time.Duration
+	Err         error // the error loading or compiling the template, if any
+}
+
+// A DropEvent describes the conversion of a drop to a Liquid value.
+type DropEvent struct {
+	parser.SourceLoc
+
+	Source  string // the source text of the template node
+	Drop    any
+	Elapsed time.Duration // the duration of the drop's ToLiquid method
+}
+
+// NopTracer is a Tracer that does nothing.
+type NopTracer struct{}
+
+func (NopTracer) EnterNode(Node)                      {}
+func (NopTracer) ExitNode(Node, time.Duration, error) {}
+func (NopTracer) EnterFilter(FilterEvent)             {}
+func (NopTracer) ExitFilter(FilterEvent)              {}
+func (NopTracer) Include(IncludeEvent)                {}
+func (NopTracer) ResolveDrop(DropEvent)               {}
+
+// filterHookFor returns a function that reports filter applications at loc to
+// the tracer, or nil if there is no tracer.
+func (c Config) filterHookFor(loc parser.Locatable) func(string, []any) func(error) {
+	t := c.Tracer
+	if t == nil {
+		return nil
+	}
+
+	return func(name string, args []any) func(error) {
+		e := FilterEvent{SourceLoc: loc.SourceLocation(), Source: loc.SourceText(), Name: name, Args: args}
+		t.EnterFilter(e)
+
+		start := time.Now()
+
+		return func(err error) {
+			e.Elapsed, e.Err = time.Since(start), err
+			t.ExitFilter(e)
+		}
+	}
+}
+
+// dropHookFor returns a function that reports drop resolutions at loc to the
+// tracer, or nil if there is no tracer.
+func (c Config) dropHookFor(loc parser.Locatable) func(any, time.Duration) {
+	t := c.Tracer
+	if t == nil {
+		return nil
+	}
+
+	return func(drop any, elapsed time.Duration) {
+		t.ResolveDrop(DropEvent{loc.SourceLocation(), loc.SourceText(), drop, elapsed})
+	}
+}
+
+// traceNode renders n, and reports it to the tracer.
+func traceNode(t Tracer, n Node, w *trimWriter, ctx nodeContext) Error {
+	t.EnterNode(n)
+
+	start := time.Now()
+	err := n.render(w, ctx)
+	t.ExitNode(n, time.Since(start), err)
+
+	return err
+}
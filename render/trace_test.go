@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/osteele/liquid/parser"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	NopTracer
+
+	events []string
+}
+
+func (r *recordingTracer) EnterNode(n Node) {
+	r.events = append(r.events, "enter "+n.SourceText())
+}
+
+func (r *recordingTracer) ExitNode(n Node, _ time.Duration, err error) {
+	r.events = append(r.events, fmt.Sprintf("exit %s %v", n.SourceText(), err))
+}
+
+func (r *recordingTracer) EnterFilter(e FilterEvent) {
+	r.events = append(r.events, fmt.Sprintf("enter filter %s%v at %s", e.Name, e.Args, e.Source))
+}
+
+func (r *recordingTracer) ExitFilter(e FilterEvent) {
+	r.events = append(r.events, fmt.Sprintf("exit filter %s %v", e.Name, e.Err))
+}
+
+func (r *recordingTracer) Include(e IncludeEvent) {
+	r.events = append(r.events, fmt.Sprintf("include %s %v", e.Filename, e.Err))
+}
+
+func (r *recordingTracer) ResolveDrop(e DropEvent) {
+	r.events = append(r.events, fmt.Sprintf("drop %v at %s", e.Drop, e.Source))
+}
+
+type traceTestDrop struct{}
+
+func (traceTestDrop) ToLiquid() any { return "dropped" }
+
+func TestRender_tracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	cfg := NewConfig()
+	addContextTestTags(cfg)
+	cfg.AddFilter("upcase", func(s string) string { return s + "!" })
+	cfg.Tracer = tracer
+
+	root, err := cfg.Compile(`{{ d | upcase }} {% test_render_file testdata/render_file.txt %}`, parser.SourceLoc{})
+	require.NoError(t, err)
+	require.NoError(t, Render(root, io.Discard, map[string]any{"d": traceTestDrop{}}, cfg))
+	require.Equal(t, []string{
+		"enter {{ d | upcase }}",
+		"drop {} at {{ d | upcase }}",
+		"enter filter upcase[dropped] at {{ d | upcase }}",
+		"exit filter upcase <nil>",
+		"exit {{ d | upcase }} <nil>",
+		"enter  ",
+		"exit   <nil>",
+		"enter {% test_render_file testdata/render_file.txt %}",
+		"include testdata/render_file.txt <nil>",
+		"enter rendered shadowed=",
+		"exit rendered shadowed= <nil>",
+		"enter {{ shadowed }}",
+		"exit {{ shadowed }} <nil>",
+		"exit {% test_render_file testdata/render_file.txt %} <nil>",
+	}, tracer.events)
+
+	tracer.events = nil
+	root, err = cfg.Compile(`{% test_render_file testdata/missing_file %}`, parser.SourceLoc{})
+	require.NoError(t, err)
+	require.Error(t, Render(root, io.Discard, nil, cfg))
+	require.Len(t, tracer.events, 3)
+	require.Contains(t, tracer.events[1], "include testdata/missing_file open testdata/missing_file")
+	require.Contains(t, tracer.events[2], "exit {% test_render_file testdata/missing_file %} ")
+}
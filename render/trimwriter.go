@@ -13,6 +13,7 @@ type trimWriter struct {
 	w    io.Writer
 	buf  bytes.Buffer
 	trim bool
+	sm   *sourceMapper // if non-nil, records the source of the bytes in buf
 }
 
 // Write writes b to the current buffer. If the trim flag is set,
@@ -21,6 +22,7 @@ type trimWriter struct {
 // set, the current buffer is flushed before b is written.
 // Write only returns the bytes written to w during a flush.
 func (tw *trimWriter) Write(b []byte) (n int, err error) {
+	data := b
 	if tw.trim {
 		b = bytes.TrimLeftFunc(b, unicode.IsSpace)
 		tw.trim = false
@@ -28,6 +30,10 @@ func (tw *trimWriter) Write(b []byte) (n int, err error) {
 		return n, err
 	}
 
+	if tw.sm != nil {
+		tw.sm.buffer(data, len(data)-len(b), tw.buf.Len())
+	}
+
 	_, err = tw.buf.Write(b)
 
 	return
@@ -37,7 +43,12 @@ func (tw *trimWriter) Write(b []byte) (n int, err error) {
 // suffix of the current buffer. It then writes the current buffer to w and
 // resets the buffer.
 func (tw *trimWriter) TrimLeft() error {
-	_, err := tw.w.Write(bytes.TrimRightFunc(tw.buf.Bytes(), unicode.IsSpace))
+	b := bytes.TrimRightFunc(tw.buf.Bytes(), unicode.IsSpace)
+	if tw.sm != nil {
+		tw.sm.flush(len(b))
+	}
+
+	_, err := tw.w.Write(b)
 	tw.buf.Reset()
 
 	return err
@@ -52,6 +63,10 @@ func (tw *trimWriter) TrimRight() {
 // Flush flushes the current buffer into w.
 func (tw *trimWriter) Flush() (int, error) {
 	if tw.buf.Len() > 0 {
+		if tw.sm != nil {
+			tw.sm.flush(tw.buf.Len())
+		}
+
 		n, err := tw.buf.WriteTo(tw.w)
 		tw.buf.Reset()
 
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/osteele/liquid/parser"
+)
+
+// A Warning is a non-fatal issue that was found during rendering: the template
+// rendered, but probably not as its author intended.
+type Warning struct {
+	parser.SourceLoc
+
+	Source  string // the source text of the template node, e.g. "{{ a < b }}"
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("Liquid warning (%s): %s in %s", w.SourceLoc, w.Message, w.Source)
+}
+
+// warningHandlerFor returns a function that reports messages as warnings at loc,
+// or nil if warnings aren't enabled.
+func (c Config) warningHandlerFor(loc parser.Locatable) func(string) {
+	handler := c.WarningHandler
+	if handler == nil {
+		return nil
+	}
+
+	return func(message string) {
+		handler(Warning{loc.SourceLocation(), loc.SourceText(), message})
+	}
+}
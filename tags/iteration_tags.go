@@ -81,12 +81,12 @@ func loopTagCompiler(node render.BlockNode) (func(io.Writer, render.Context) err
 			return err
 		}
 
-		iter := makeIterator(val)
-		if iter == nil {
+		items := makeLoopItems(val)
+		if items == nil {
 			return nil
 		}
 
-		iter, err = applyLoopModifiers(stmt.Loop, ctx, iter)
+		items, err = applyLoopModifiers(stmt.Loop, ctx, items)
 		if err != nil {
 			return err
 		}
@@ -95,11 +95,12 @@ func loopTagCompiler(node render.BlockNode) (func(io.Writer, render.Context) err
 			return errors.New("for loops accept at most one else clause")
 		}
 
-		if iter.Len() == 0 && len(node.Clauses) == 1 && node.Clauses[0].Name == "else" {
+		empty, err := loopRenderer{stmt.Loop, node.Name}.render(items, w, ctx)
+		if err == nil && empty && len(node.Clauses) == 1 && node.Clauses[0].Name == "else" {
 			return ctx.RenderBlock(w, node.Clauses[0])
 		}
 
-		return loopRenderer{stmt.Loop, node.Name}.render(iter, w, ctx)
+		return err
 	}, nil
 }
 
@@ -109,11 +110,12 @@ type loopRenderer struct {
 	tagName string
 }
 
-func (loop loopRenderer) render(iter iterable, w io.Writer, ctx render.Context) error {
+// render renders the loop body for each item. It returns true if there are no items.
+func (loop loopRenderer) render(items loopItems, w io.Writer, ctx render.Context) (bool, error) {
 	// loop decorator
 	decorator, err := makeLoopDecorator(loop, ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// shallow-bind the loop variables; restore on exit
@@ -122,39 +124,49 @@ func (loop loopRenderer) render(iter iterable, w io.Writer, ctx render.Context)
 		ctx.Set(loop.Variable, forloop)
 	}(ctx.Get(forloopVarName), ctx.Get(loop.Variable))
 
-	cycleMap := map[string]int{}
+	var (
+		cycleMap = map[string]int{}
+		l        = items.size()
+		i        = 0
+	)
 
-loop:
-
-	for i, l := 0, iter.Len(); i < l; i++ {
-		ctx.Set(loop.Variable, iter.Index(i))
-		ctx.Set(forloopVarName, map[string]any{
+	items.each(func(item any, last bool) bool {
+		forloop := map[string]any{
 			"first":   i == 0,
-			"last":    i == l-1,
+			"last":    last,
 			"index":   i + 1,
 			"index0":  i,
-			"rindex":  l - i,
-			"rindex0": l - i - 1,
-			"length":  l,
 			".cycles": cycleMap,
-		})
+		}
+		// The length of a lazy sequence isn't known until it has been iterated.
+		if l >= 0 {
+			forloop["rindex"] = l - i
+			forloop["rindex0"] = l - i - 1
+			forloop["length"] = l
+		}
+
+		ctx.Set(loop.Variable, item)
+		ctx.Set(forloopVarName, forloop)
 		decorator.before(w, i)
-		err := ctx.RenderChildren(w)
-		decorator.after(w, i, l)
+		rerr := ctx.RenderChildren(w)
+		decorator.after(w, i, last)
+		i++
 
 		switch {
-		case err == nil:
+		case rerr == nil:
 		// fall through
-		case err.Cause() == errLoopBreak:
-			break loop
-		case err.Cause() == errLoopContinueLoop:
-			continue loop
+		case rerr.Cause() == errLoopBreak:
+			return false
+		case rerr.Cause() == errLoopContinueLoop:
 		default:
-			return err
+			err = rerr
+			return false
 		}
-	}
 
-	return nil
+		return true
+	})
+
+	return i == 0, err
 }
 
 func makeLoopDecorator(loop loopRenderer, ctx render.Context) (loopDecorator, error) {
@@ -182,14 +194,14 @@ func makeLoopDecorator(loop loopRenderer, ctx render.Context) (loopDecorator, er
 }
 
 type loopDecorator interface {
-	before(io.Writer, int)
-	after(io.Writer, int, int)
+	before(w io.Writer, i int)
+	after(w io.Writer, i int, last bool)
 }
 
 type forLoopDecorator struct{}
 
-func (d forLoopDecorator) before(io.Writer, int)     {}
-func (d forLoopDecorator) after(io.Writer, int, int) {}
+func (d forLoopDecorator) before(io.Writer, int)      {}
+func (d forLoopDecorator) after(io.Writer, int, bool) {}
 
 type tableRowDecorator int
 
@@ -208,23 +220,30 @@ func (c tableRowDecorator) before(w io.Writer, i int) {
 	}
 }
 
-func (c tableRowDecorator) after(w io.Writer, i, l int) {
+func (c tableRowDecorator) after(w io.Writer, i int, last bool) {
 	cols := int(c)
 
 	if _, err := io.WriteString(w, `</td>`); err != nil {
 		panic(err)
 	}
 
-	if (i+1)%cols == 0 || i+1 == l {
+	if (i+1)%cols == 0 || last {
 		if _, err := io.WriteString(w, `</tr>`); err != nil {
 			panic(err)
 		}
 	}
 }
 
-func applyLoopModifiers(loop expressions.Loop, ctx render.Context, iter iterable) (iterable, error) {
+// applyLoopModifiers applies the reversed, offset and limit modifiers, in that order.
+// Reversing a lazy sequence reads all its items. Offset and limit read only as many
+// items as they need.
+func applyLoopModifiers(loop expressions.Loop, ctx render.Context, items loopItems) (loopItems, error) {
 	if loop.Reversed {
-		iter = reverseWrapper{iter}
+		if s, ok := items.(lazySequence); ok {
+			items = indexedItems{s.collect()}
+		}
+
+		items = indexedItems{reverseWrapper{items.(indexedItems).iterable}}
 	}
 
 	if loop.Offset != nil {
@@ -239,7 +258,7 @@ func applyLoopModifiers(loop expressions.Loop, ctx render.Context, iter iterable
 		}
 
 		if offset > 0 {
-			iter = offsetWrapper{iter, offset}
+			items = offsetItems(items, offset)
 		}
 	}
 
@@ -255,11 +274,41 @@ func applyLoopModifiers(loop expressions.Loop, ctx render.Context, iter iterable
 		}
 
 		if limit >= 0 {
-			iter = limitWrapper{iter, limit}
+			items = limitItems(items, limit)
 		}
 	}
 
-	return iter, nil
+	return items, nil
+}
+
+func offsetItems(items loopItems, n int) loopItems {
+	if s, ok := items.(lazySequence); ok {
+		return s.skip(n)
+	}
+
+	return indexedItems{offsetWrapper{items.(indexedItems).iterable, n}}
+}
+
+func limitItems(items loopItems, n int) loopItems {
+	if s, ok := items.(lazySequence); ok {
+		return s.take(n)
+	}
+
+	return indexedItems{limitWrapper{items.(indexedItems).iterable, n}}
+}
+
+// makeLoopItems returns the items of a value that a loop iterates over, or nil
+// if the value isn't iterable.
+func makeLoopItems(value any) loopItems {
+	if it := makeIterator(value); it != nil {
+		return indexedItems{it}
+	}
+
+	if s, ok := makeLazySequence(value); ok {
+		return s
+	}
+
+	return nil
 }
 
 func makeIterator(value any) iterable {
@@ -272,6 +321,9 @@ func makeIterator(value any) iterable {
 	}
 
 	switch value := value.(type) {
+	case liquidIterable:
+		// read by makeLazySequence, even if it is a slice or a map
+		return nil
 	case IterationKeyedMap:
 		return makeIterationKeyedMap(value)
 	case yaml.MapSlice:
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"iter"
 	"regexp"
 	"strings"
 	"testing"
@@ -214,3 +215,91 @@ func TestIterationTags_errors(t *testing.T) {
 		})
 	}
 }
+
+// countingSeq returns an iter.Seq of the integers from 1 to n, and a pointer to the number of items read from it.
+func countingSeq(n int) (iter.Seq[int], *int) {
+	count := 0
+
+	return func(yield func(int) bool) {
+		for i := 1; i <= n; i++ {
+			count++
+			if !yield(i) {
+				return
+			}
+		}
+	}, &count
+}
+
+// sizedSeq implements the liquid package's Iterable and Sizer interfaces. It
+// yields its items in upper case, so that a loop over it as a slice shows.
+type sizedSeq []string
+
+func (s sizedSeq) LiquidEach(yield func(any) bool) {
+	for _, item := range s {
+		if !yield(strings.ToUpper(item)) {
+			return
+		}
+	}
+}
+
+func (s sizedSeq) LiquidSize() int { return len(s) }
+
+func TestIterationTags_sequences(t *testing.T) {
+	cfg := render.NewConfig()
+	AddStandardTags(&cfg)
+
+	tests := []struct {
+		in, expected string
+		value        func() any
+	}{
+		{`{% for a in seq %}{{ a }}.{% endfor %}`, "1.2.3.", func() any { s, _ := countingSeq(3); return s }},
+		{`{% for a in seq %}{{ a[0] }}={{ a[1] }}.{% endfor %}`, "a=1.b=2.", func() any {
+			return iter.Seq2[string, int](func(yield func(string, int) bool) {
+				_ = yield("a", 1) && yield("b", 2)
+			})
+		}},
+		{`{% for a in seq %}{{ a }}.{% endfor %}`, "x.y.", func() any {
+			ch := make(chan string, 2)
+			ch <- "x"
+			ch <- "y"
+			close(ch)
+
+			return (<-chan string)(ch)
+		}},
+		{`{% for a in seq %}{{ a }}.{% else %}empty{% endfor %}`, "empty", func() any { s, _ := countingSeq(0); return s }},
+		{`{% for a in seq offset: 1 limit: 2 %}{{ a }}.{% endfor %}`, "2.3.", func() any { s, _ := countingSeq(5); return s }},
+		{`{% for a in seq reversed limit: 2 %}{{ a }}.{% endfor %}`, "3.2.", func() any { s, _ := countingSeq(3); return s }},
+		{`{% for a in seq %}{{ forloop.index }}{{ forloop.first }}{{ forloop.last }}[{{ forloop.length }}].{% endfor %}`, "1truefalse[].2falsetrue[].", func() any { s, _ := countingSeq(2); return s }},
+		{`{% for a in seq %}{{ forloop.rindex }}{{ forloop.last }}.{% endfor %}`, "2false.1true.", func() any { return sizedSeq{"a", "b"} }},
+		{`{% for a in seq %}{{ a }}.{% endfor %}`, "A.B.", func() any { return sizedSeq{"a", "b"} }},
+		{`{% tablerow a in seq cols: 2 %}{{ a }}{% endtablerow %}`, `<tr class="row1"><td class="col1">1</td><td class="col2">2</td></tr><tr class="row2"><td class="col1">3</td></tr>`, func() any { s, _ := countingSeq(3); return s }},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%02d", i+1), func(t *testing.T) {
+			root, err := cfg.Compile(test.in, parser.SourceLoc{})
+			require.NoErrorf(t, err, test.in)
+
+			buf := new(bytes.Buffer)
+			err = render.Render(root, buf, map[string]any{"seq": test.value()}, cfg)
+			require.NoErrorf(t, err, test.in)
+			require.Equalf(t, test.expected, buf.String(), test.in)
+		})
+	}
+
+	// limit and break stop reading the sequence
+	for _, test := range []struct {
+		in    string
+		count int
+	}{
+		{`{% for a in seq limit: 2 %}{{ a }}{% endfor %}`, 2},
+		{`{% for a in seq offset: 1 limit: 2 %}{{ a }}{% endfor %}`, 3},
+		{`{% for a in seq %}{% if a == 2 %}{% break %}{% endif %}{% endfor %}`, 3},
+	} {
+		seq, count := countingSeq(1000)
+		root, err := cfg.Compile(test.in, parser.SourceLoc{})
+		require.NoError(t, err)
+		require.NoError(t, render.Render(root, io.Discard, map[string]any{"seq": seq}, cfg))
+		require.Equal(t, test.count, *count, test.in)
+	}
+}
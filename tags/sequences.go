@@ -0,0 +1,203 @@
+package tags
+
+import (
+	"iter"
+	"reflect"
+)
+
+// loopItems are the items of a for or tablerow loop.
+type loopItems interface {
+	// each calls yield with each item, and whether it is the last item, until yield returns false.
+	each(yield func(item any, last bool) bool)
+	// size returns the number of items, or -1 if it isn't known before they are iterated.
+	size() int
+}
+
+// indexedItems are the items of an iterable, that is indexed by position.
+type indexedItems struct{ iterable }
+
+func (items indexedItems) size() int { return items.Len() }
+
+func (items indexedItems) each(yield func(any, bool) bool) {
+	for i, n := 0, items.Len(); i < n; i++ {
+		if !yield(items.Index(i), i == n-1) {
+			return
+		}
+	}
+}
+
+// A lazySequence is a sequence, such as an iter.Seq or a channel, whose items
+// are produced as the loop iterates, instead of being loaded into memory.
+type lazySequence struct {
+	seq    iter.Seq[any]
+	length int // the number of items, or -1 if it isn't known
+}
+
+func (s lazySequence) size() int { return s.length }
+
+func (s lazySequence) each(yield func(any, bool) bool) {
+	if s.length >= 0 {
+		i := 0
+		for item := range s.seq {
+			if !yield(item, i == s.length-1) {
+				return
+			}
+
+			i++
+		}
+
+		return
+	}
+
+	// Read one item ahead, to tell whether the current item is the last.
+	var (
+		prev    any
+		hasPrev bool
+	)
+
+	for item := range s.seq {
+		if hasPrev && !yield(prev, false) {
+			return
+		}
+
+		prev, hasPrev = item, true
+	}
+
+	if hasPrev {
+		yield(prev, true)
+	}
+}
+
+// skip returns the sequence without its first n items.
+func (s lazySequence) skip(n int) lazySequence {
+	length := s.length
+	if length >= 0 {
+		length = intMax(0, length-n)
+	}
+
+	return lazySequence{func(yield func(any) bool) {
+		i := 0
+		for item := range s.seq {
+			if i < n {
+				i++
+				continue
+			}
+
+			if !yield(item) {
+				return
+			}
+		}
+	}, length}
+}
+
+// take returns the first n items of the sequence. It stops reading the sequence
+// after the nth item.
+func (s lazySequence) take(n int) lazySequence {
+	length := s.length
+	if length >= 0 {
+		length = intMin(n, length)
+	}
+
+	return lazySequence{func(yield func(any) bool) {
+		if n <= 0 {
+			return
+		}
+
+		i := 0
+		for item := range s.seq {
+			if !yield(item) {
+				return
+			}
+
+			i++
+			if i == n {
+				return
+			}
+		}
+	}, length}
+}
+
+// collect reads the items of the sequence into an iterable.
+func (s lazySequence) collect() iterable {
+	items := []any{}
+	for item := range s.seq {
+		items = append(items, item)
+	}
+
+	return sliceWrapper(reflect.ValueOf(items))
+}
+
+// The methods of the liquid package's Iterable and Sizer interfaces.
+type (
+	liquidIterable interface {
+		LiquidEach(yield func(any) bool)
+	}
+	liquidSizer interface {
+		LiquidSize() int
+	}
+)
+
+// makeLazySequence returns a lazySequence for an iter.Seq or iter.Seq2 of any
+// type, a channel that can be received from, or a value that implements the
+// liquid package's Iterable interface. An iter.Seq2 yields [key, value] pairs,
+// as a map does.
+func makeLazySequence(value any) (lazySequence, bool) {
+	switch value := value.(type) {
+	case nil:
+		return lazySequence{}, false
+	case iter.Seq[any]:
+		return lazySequence{value, -1}, true
+	case func(func(any) bool):
+		return lazySequence{value, -1}, true
+	case liquidIterable:
+		length := -1
+		if s, ok := value.(liquidSizer); ok {
+			length = s.LiquidSize()
+		}
+
+		return lazySequence{value.LiquidEach, length}, true
+	}
+
+	rv := reflect.ValueOf(value)
+	rt := rv.Type()
+
+	switch rt.Kind() {
+	case reflect.Chan:
+		if rt.ChanDir()&reflect.RecvDir == 0 {
+			return lazySequence{}, false
+		}
+
+		return lazySequence{func(yield func(any) bool) {
+			for {
+				item, ok := rv.Recv()
+				if !ok || !yield(item.Interface()) {
+					return
+				}
+			}
+		}, -1}, true
+	case reflect.Func:
+		if rv.IsNil() || rt.NumIn() != 1 || rt.NumOut() != 0 {
+			return lazySequence{}, false
+		}
+
+		yt := rt.In(0)
+		if yt.Kind() != reflect.Func || yt.NumOut() != 1 || yt.Out(0).Kind() != reflect.Bool || yt.NumIn() < 1 || yt.NumIn() > 2 {
+			return lazySequence{}, false
+		}
+
+		return lazySequence{func(yield func(any) bool) {
+			rv.Call([]reflect.Value{reflect.MakeFunc(yt, func(args []reflect.Value) []reflect.Value {
+				var item any
+				if len(args) == 1 {
+					item = args[0].Interface()
+				} else {
+					item = []any{args[0].Interface(), args[1].Interface()}
+				}
+
+				return []reflect.Value{reflect.ValueOf(yield(item)).Convert(yt.Out(0))}
+			})})
+		}, -1}, true
+	default:
+		return lazySequence{}, false
+	}
+}
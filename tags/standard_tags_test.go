@@ -24,7 +24,7 @@ var tagTests = []struct{ in, expected string }{
 	// variable tags
 	{`{% assign av = 1 %}{{ av }}`, "1"},
 	{`{% assign av = obj.a %}{{ av }}`, "1"},
-	{`{% assign av = (1..5) %}{{ av }}`, "{1 5}"},
+	{`{% assign av = (1..5) %}{{ av }}`, "1..5"},
 	{`{% capture x %}captured{% endcapture %}{{ x }}`, "captured"},
 
 	// issue #76: assign with boolean expressions using 'and'/'or' operators
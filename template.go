@@ -14,17 +14,67 @@ import (
 type Template struct {
 	root render.Node
 	cfg  *render.Config
-}
 
-func newTemplate(cfg *render.Config, source []byte, path string, line int) (*Template, SourceError) {
-	loc := parser.SourceLoc{Pathname: path, LineNo: line}
+	frontMatter         map[string]any
+	frontMatterVariable string
+}
 
-	root, err := cfg.Compile(string(source), loc)
+func newTemplate(e *Engine, source []byte, path string, line int) (*Template, SourceError) {
+	fm, source, loc, err := e.removeFrontMatter(source, parser.SourceLoc{Pathname: path, LineNo: line})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Template{root, cfg}, nil
+	root, perr := e.cfg.Compile(string(source), loc)
+	if perr != nil {
+		return nil, perr
+	}
+
+	t := &Template{root: root, cfg: &e.cfg}
+	if fm != nil {
+		t.frontMatter, t.frontMatterVariable = fm, e.frontMatterVariable
+	}
+
+	return t, nil
+}
+
+// FrontMatter returns the template's YAML front matter, or nil if it doesn't have any.
+// See Engine.EnableFrontMatter.
+func (t *Template) FrontMatter() map[string]any {
+	return t.frontMatter
+}
+
+// bindings returns vars, with the front matter bound to its variable. If vars already
+// has a map for the variable, the front matter is merged into it, with the values in vars
+// taking precedence.
+func (t *Template) bindings(vars Bindings) Bindings {
+	name := t.frontMatterVariable
+	if t.frontMatter == nil || name == "" {
+		return vars
+	}
+
+	b := make(Bindings, len(vars)+1)
+	for k, v := range vars {
+		b[k] = v
+	}
+
+	page := make(map[string]any, len(t.frontMatter))
+	for k, v := range t.frontMatter {
+		page[k] = v
+	}
+
+	switch v := vars[name].(type) {
+	case nil:
+		b[name] = page
+	case map[string]any:
+		for k, v := range v {
+			page[k] = v
+		}
+
+		b[name] = page
+	}
+
+	return b
 }
 
 // GetRoot returns the root node of the abstract syntax tree (AST) representing
@@ -37,7 +87,7 @@ func (t *Template) GetRoot() render.Node {
 func (t *Template) Render(vars Bindings) ([]byte, SourceError) {
 	buf := new(bytes.Buffer)
 
-	err := render.Render(t.root, buf, vars, *t.cfg)
+	err := render.Render(t.root, buf, t.bindings(vars), *t.cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +97,7 @@ func (t *Template) Render(vars Bindings) ([]byte, SourceError) {
 
 // FRender executes the template with the specified variable bindings and renders it into w.
 func (t *Template) FRender(w io.Writer, vars Bindings) SourceError {
-	err := render.Render(t.root, w, vars, *t.cfg)
+	err := render.Render(t.root, w, t.bindings(vars), *t.cfg)
 	if err != nil {
 		return err
 	}
@@ -55,6 +105,77 @@ func (t *Template) FRender(w io.Writer, vars Bindings) SourceError {
 	return nil
 }
 
+// RenderWithWarnings is the same as Render, except that it also returns the warnings
+// that were found during rendering. Warnings are also passed to the engine's warning handler,
+// if there is one.
+func (t *Template) RenderWithWarnings(vars Bindings) ([]byte, []render.Warning, SourceError) {
+	var warnings []render.Warning
+
+	cfg := *t.cfg
+	handler := cfg.WarningHandler
+	cfg.WarningHandler = func(w render.Warning) {
+		warnings = append(warnings, w)
+		if handler != nil {
+			handler(w)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := render.Render(t.root, buf, t.bindings(vars), cfg)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	return buf.Bytes(), warnings, nil
+}
+
+// RenderWithDebugger is the same as Render, except that d is called before each
+// template node is rendered. The debugger can inspect the variable bindings, evaluate
+// expressions, and pause the render by not returning.
+func (t *Template) RenderWithDebugger(vars Bindings, d render.Debugger) ([]byte, SourceError) {
+	cfg := *t.cfg
+	cfg.Debugger = d
+
+	buf := new(bytes.Buffer)
+
+	err := render.Render(t.root, buf, t.bindings(vars), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderProfiled is the same as Render, except that it also returns a profile of the
+// time that was spent rendering each template node, and of the memory if opts.Memory
+// is set. Use Profile.WriteReport to print the hot spots, by template line, filter,
+// and included template.
+func (t *Template) RenderProfiled(vars Bindings, opts render.ProfileOptions) ([]byte, *render.Profile, SourceError) {
+	buf := new(bytes.Buffer)
+
+	profile, err := render.RenderProfiled(t.root, buf, t.bindings(vars), *t.cfg, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), profile, nil
+}
+
+// RenderWithSourceMap is the same as Render, except that it also returns a map from
+// ranges of the output to the template nodes that produced them.
+// Use SourceMap.MarshalV3 to encode the map as a standard source map.
+func (t *Template) RenderWithSourceMap(vars Bindings) ([]byte, *render.SourceMap, SourceError) {
+	buf := new(bytes.Buffer)
+
+	sm, err := render.RenderWithSourceMap(t.root, buf, t.bindings(vars), *t.cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), sm, nil
+}
+
 // RenderString is a convenience wrapper for Render, that has string input and output.
 func (t *Template) RenderString(b Bindings) (string, SourceError) {
 	bs, err := t.Render(b)
@@ -129,3 +129,70 @@ func BenchmarkTemplate_Render(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+type debuggerFunc func(render.DebugState) error
+
+func (f debuggerFunc) Break(s render.DebugState) error { return f(s) }
+
+func TestTemplate_RenderWithDebugger(t *testing.T) {
+	var indices []any
+
+	engine := NewEngine()
+	tpl, err := engine.ParseString(`{% for x in (1..3) %}{{ x }}{% endfor %}`)
+	require.NoError(t, err)
+
+	out, err := tpl.RenderWithDebugger(nil, debuggerFunc(func(s render.DebugState) error {
+		if s.Node.SourceText() == "{{ x }}" {
+			index, err := s.Evaluate("forloop.index")
+			require.NoError(t, err)
+			require.Equal(t, index, s.Bindings()["forloop"].(map[string]any)["index"])
+			indices = append(indices, index)
+		}
+
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "123", string(out))
+	require.Equal(t, []any{1, 2, 3}, indices)
+}
+
+func TestTemplate_RenderProfiled(t *testing.T) {
+	engine := NewEngine()
+	tpl, err := engine.ParseTemplateLocation([]byte("{% for x in (1..3) %}\n{{ x | plus: 1 }}{% endfor %}"), "page.html", 1)
+	require.NoError(t, err)
+
+	out, profile, err := tpl.RenderProfiled(nil, render.ProfileOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "\n2\n3\n4", string(out))
+	require.Len(t, profile.Nodes, 1)
+	require.Len(t, profile.Nodes[0].Children, 6)
+	require.Len(t, profile.Filters(), 1)
+	require.Equal(t, "plus", profile.Filters()[0].Name)
+	require.Equal(t, 3, profile.Filters()[0].Calls)
+}
+
+func TestTemplate_RenderWithSourceMap(t *testing.T) {
+	engine := NewEngine()
+	_, err := engine.ParseTemplateAndCache([]byte("<b>{{ x }}</b>"), "partial.html", 1)
+	require.NoError(t, err)
+
+	tpl, err := engine.ParseTemplateLocation([]byte("Hi {% include 'partial.html' %}!"), "page.html", 1)
+	require.NoError(t, err)
+
+	out, sm, err := tpl.RenderWithSourceMap(Bindings{"x": "there"})
+	require.NoError(t, err)
+	require.Equal(t, "Hi <b>there</b>!", string(out))
+
+	var sources []string
+	for _, seg := range sm.Segments {
+		sources = append(sources, fmt.Sprintf("%s=%s:%s", out[seg.Start:seg.End], seg.Pathname, seg.Source))
+	}
+
+	require.Equal(t, []string{
+		"Hi =page.html:Hi ",
+		"<b>=partial.html:<b>",
+		"there=partial.html:{{ x }}",
+		"</b>=partial.html:</b>",
+		"!=page.html:!",
+	}, sources)
+}
@@ -8,10 +8,23 @@ import (
 // TODO Length is now only used by the "size" filter.
 // Maybe it should go somewhere else.
 
-// Length returns the length of a string or array. In keeping with Liquid semantics,
-// and contra Go, it does not return the size of a map.
+// Length returns the length of a string or array, the size of a value that implements
+// the Sizer or Iterable interface of the liquid package, or the Len of a value that
+// has that method. In keeping with Liquid semantics, and contra Go, it does not return
+// the size of a map.
 func Length(value any) int {
 	value = ToLiquid(value)
+	if s, ok := value.(sizer); ok {
+		return s.LiquidSize()
+	}
+
+	if l, ok := value.(lengther); ok {
+		return l.Len()
+	}
+
+	if items, ok := Items(value); ok {
+		return len(items)
+	}
 
 	ref := reflect.ValueOf(value)
 	switch ref.Kind() {
@@ -0,0 +1,482 @@
+package values
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// This file implements arithmetic and comparison on big numbers: *big.Int,
+// *big.Rat, *big.Float, and values that implement the liquid package's Decimal
+// interface.
+
+// decimal is the liquid package's Decimal interface, which documents it.
+type decimal interface {
+	LiquidRat() *big.Rat
+	LiquidFromRat(r *big.Rat) any
+}
+
+// ErrDivisionByZero is returned by BigQuo if the divisor is zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// A numberKind orders the kinds of numbers. An arithmetic operation on two
+// numbers returns a number of the greater of their kinds.
+type numberKind int
+
+const (
+	notNumber numberKind = iota
+	intNumber
+	bigIntNumber
+	ratNumber
+	floatNumber
+	bigFloatNumber
+	decimalNumber
+)
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+)
+
+func kindOfNumber(value any) numberKind {
+	switch value := value.(type) {
+	case *big.Int:
+		if value != nil {
+			return bigIntNumber
+		}
+	case *big.Rat:
+		if value != nil {
+			return ratNumber
+		}
+	case *big.Float:
+		if value != nil {
+			return bigFloatNumber
+		}
+	case decimal:
+		return decimalNumber
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return intNumber
+	case float32, float64:
+		return floatNumber
+	}
+
+	return notNumber
+}
+
+// IsBigNumber returns a bool indicating whether value is a *big.Int, *big.Rat or
+// *big.Float, or implements the liquid package's Decimal interface.
+func IsBigNumber(value any) bool {
+	return kindOfNumber(value) >= bigIntNumber && kindOfNumber(value) != floatNumber
+}
+
+// FormatBigNumber returns the text of a big number, for output. The second
+// result is false if value isn't a big number.
+func FormatBigNumber(value any) (string, bool) {
+	switch value := value.(type) {
+	case *big.Int:
+		return value.String(), value != nil
+	case *big.Rat:
+		return value.RatString(), value != nil
+	case *big.Float:
+		return value.Text('g', -1), value != nil
+	case decimal:
+		if s, ok := value.(fmt.Stringer); ok {
+			return s.String(), true
+		}
+
+		return value.LiquidRat().RatString(), true
+	}
+
+	return "", false
+}
+
+func toBigInt(value any) *big.Int {
+	switch value := value.(type) {
+	case *big.Int:
+		return value
+	case uint:
+		return new(big.Int).SetUint64(uint64(value))
+	case uint64:
+		return new(big.Int).SetUint64(value)
+	default:
+		return big.NewInt(reflect.ValueOf(value).Convert(int64Type).Int())
+	}
+}
+
+// toRat returns the exact value of a number. A float is converted from its
+// shortest decimal representation, so that 0.1 is 1/10. It returns nil for
+// infinities and NaN.
+func toRat(value any) *big.Rat {
+	switch value := value.(type) {
+	case *big.Rat:
+		return value
+	case *big.Float:
+		if value.IsInf() {
+			return nil
+		}
+
+		r, _ := value.Rat(nil)
+
+		return r
+	case decimal:
+		return value.LiquidRat()
+	case float32, float64:
+		f := reflect.ValueOf(value).Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil
+		}
+
+		r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+
+		return r
+	default:
+		return new(big.Rat).SetInt(toBigInt(value))
+	}
+}
+
+func toFloat(value any) float64 {
+	switch value := value.(type) {
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(value).Float64()
+		return f
+	case *big.Rat:
+		f, _ := value.Float64()
+		return f
+	case *big.Float:
+		f, _ := value.Float64()
+		return f
+	case decimal:
+		f, _ := value.LiquidRat().Float64()
+		return f
+	default:
+		return reflect.ValueOf(value).Convert(float64Type).Float()
+	}
+}
+
+// toBigFloat converts a number to a *big.Float with precision prec.
+func toBigFloat(value any, prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec)
+
+	switch value := value.(type) {
+	case *big.Float:
+		return f.Set(value)
+	case float32, float64:
+		return f.SetFloat64(toFloat(value))
+	case *big.Int:
+		return f.SetInt(value)
+	default:
+		if r := toRat(value); r != nil {
+			return f.SetRat(r)
+		}
+
+		return f
+	}
+}
+
+// bigFloatPrec returns the greater of the precisions of the *big.Float operands.
+func bigFloatPrec(a, b any) uint {
+	var prec uint
+
+	for _, v := range []any{a, b} {
+		if f, ok := v.(*big.Float); ok {
+			prec = max(prec, f.Prec())
+		}
+	}
+
+	return prec
+}
+
+// fromRat converts r to the kind of the first decimal among the operands.
+func fromRat(r *big.Rat, a, b any) any {
+	for _, v := range []any{a, b} {
+		if d, ok := v.(decimal); ok {
+			return d.LiquidFromRat(r)
+		}
+	}
+
+	return r
+}
+
+// bigArithmetic applies an operation to two numbers, in the greater of their kinds.
+func bigArithmetic(
+	a, b any,
+	intOp func(z, x, y *big.Int) *big.Int,
+	ratOp func(z, x, y *big.Rat) *big.Rat,
+	floatOp func(x, y float64) float64,
+	bigFloatOp func(z, x, y *big.Float) *big.Float,
+) (any, error) {
+	ka, kb := kindOfNumber(a), kindOfNumber(b)
+	if ka == notNumber || kb == notNumber {
+		return nil, typeErrorf("can't do arithmetic on %T and %T", a, b)
+	}
+
+	switch max(ka, kb) {
+	case intNumber, bigIntNumber:
+		return intOp(new(big.Int), toBigInt(a), toBigInt(b)), nil
+	case ratNumber:
+		return ratOp(new(big.Rat), toRat(a), toRat(b)), nil
+	case floatNumber:
+		return floatOp(toFloat(a), toFloat(b)), nil
+	case bigFloatNumber:
+		prec := bigFloatPrec(a, b)
+		return bigFloatOp(new(big.Float).SetPrec(prec), toBigFloat(a, prec), toBigFloat(b, prec)), nil
+	default:
+		x, y := toRat(a), toRat(b)
+		if x == nil || y == nil {
+			return nil, typeErrorf("can't convert %v or %v to a decimal", a, b)
+		}
+
+		return fromRat(ratOp(new(big.Rat), x, y), a, b), nil
+	}
+}
+
+// BigAdd returns a + b, as the greater of the kinds of a and b in the order int,
+// *big.Int, *big.Rat, float64, *big.Float, Decimal. The sum of two ints is a *big.Int.
+func BigAdd(a, b any) (any, error) {
+	return bigArithmetic(a, b, (*big.Int).Add, (*big.Rat).Add,
+		func(x, y float64) float64 { return x + y }, (*big.Float).Add)
+}
+
+// BigSub returns a - b, as the greater of the kinds of a and b; see BigAdd.
+func BigSub(a, b any) (any, error) {
+	return bigArithmetic(a, b, (*big.Int).Sub, (*big.Rat).Sub,
+		func(x, y float64) float64 { return x - y }, (*big.Float).Sub)
+}
+
+// BigMul returns a * b, as the greater of the kinds of a and b; see BigAdd.
+func BigMul(a, b any) (any, error) {
+	return bigArithmetic(a, b, (*big.Int).Mul, (*big.Rat).Mul,
+		func(x, y float64) float64 { return x * y }, (*big.Float).Mul)
+}
+
+// BigQuo returns a / b, as the greater of the kinds of a and b; see BigAdd. The
+// quotient of integers is truncated, as for int64.
+func BigQuo(a, b any) (any, error) {
+	if kindOfNumber(b) == notNumber {
+		return nil, fmt.Errorf("invalid divisor: '%v'", b)
+	}
+
+	if r := toRat(b); r != nil && r.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	return bigArithmetic(a, b, (*big.Int).Quo, (*big.Rat).Quo,
+		func(x, y float64) float64 { return x / y }, (*big.Float).Quo)
+}
+
+// BigRound rounds a big number to places decimal places, with halves rounded away
+// from zero. The result has the kind of a.
+func BigRound(a any, places int) any {
+	return bigRound(a, func(r *big.Rat) *big.Int {
+		// round half away from zero
+		q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+		if new(big.Int).Mul(m.Abs(m), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+			q.Add(q, big.NewInt(int64(r.Sign())))
+		}
+
+		return q
+	}, places)
+}
+
+// BigCeil returns the least integer value greater than or equal to a big number,
+// as the kind of a.
+func BigCeil(a any) any {
+	return bigRound(a, func(r *big.Rat) *big.Int {
+		q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+		if m.Sign() != 0 {
+			q.Add(q, big.NewInt(1))
+		}
+
+		return q
+	}, 0)
+}
+
+// BigFloor returns the greatest integer value less than or equal to a big number,
+// as the kind of a.
+func BigFloor(a any) any {
+	return bigRound(a, func(r *big.Rat) *big.Int {
+		q, _ := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+		return q
+	}, 0)
+}
+
+// BigAbs returns the absolute value of a big number, as the kind of a.
+func BigAbs(a any) any {
+	switch a := a.(type) {
+	case *big.Int:
+		return new(big.Int).Abs(a)
+	case *big.Rat:
+		return new(big.Rat).Abs(a)
+	case *big.Float:
+		return new(big.Float).Abs(a)
+	case decimal:
+		return a.LiquidFromRat(new(big.Rat).Abs(a.LiquidRat()))
+	default:
+		return a
+	}
+}
+
+// bigRound rounds a to places decimal places with round, which rounds a rational
+// number to an integer.
+func bigRound(a any, round func(*big.Rat) *big.Int, places int) any {
+	if _, ok := a.(*big.Int); ok && places >= 0 {
+		return a
+	}
+
+	r := toRat(a)
+	if r == nil {
+		return a
+	}
+
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(places))), nil))
+	if places < 0 {
+		scale.Inv(scale)
+	}
+
+	rounded := new(big.Rat).SetInt(round(new(big.Rat).Mul(r, scale)))
+	rounded.Quo(rounded, scale)
+
+	switch a := a.(type) {
+	case *big.Int:
+		return new(big.Int).Quo(rounded.Num(), rounded.Denom())
+	case *big.Float:
+		return new(big.Float).SetPrec(a.Prec()).SetRat(rounded)
+	case decimal:
+		return a.LiquidFromRat(rounded)
+	default:
+		return rounded
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// compareBig compares two numbers, at least one of which is a big number. The
+// second result is false if they can't be compared.
+func compareBig(a, b any) (int, bool) {
+	ka, kb := kindOfNumber(a), kindOfNumber(b)
+	if ka == notNumber || kb == notNumber {
+		return 0, false
+	}
+
+	if ka <= bigIntNumber && kb <= bigIntNumber {
+		return toBigInt(a).Cmp(toBigInt(b)), true
+	}
+
+	x, y := toRat(a), toRat(b)
+	if x == nil || y == nil {
+		// an infinity
+		return toBigFloat(a, 64).Cmp(toBigFloat(b, 64)), !math.IsNaN(toFloat(a)) && !math.IsNaN(toFloat(b))
+	}
+
+	return x.Cmp(y), true
+}
+
+// convertBig converts between big numbers, and other numbers and strings. The
+// second result is false if neither value nor typ is a big number.
+func convertBig(value any, typ reflect.Type) (any, bool, error) {
+	if typ.Kind() == reflect.Interface || reflect.TypeOf(value) == typ {
+		return nil, false, nil
+	}
+
+	switch typ {
+	case bigIntType, bigRatType, bigFloatType:
+		if s, ok := value.(string); ok {
+			return parseBig(s, typ)
+		}
+
+		if kindOfNumber(value) == notNumber {
+			return nil, true, conversionError("", value, typ)
+		}
+
+		switch typ {
+		case bigIntType:
+			if i := truncate(value); i != nil {
+				return i, true, nil
+			}
+		case bigRatType:
+			if r := toRat(value); r != nil {
+				return r, true, nil
+			}
+		default:
+			return toBigFloat(value, max(bigFloatPrec(value, nil), 64)), true, nil
+		}
+
+		return nil, true, conversionError("", value, typ)
+	}
+
+	if !IsBigNumber(value) {
+		return nil, false, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := truncate(value)
+		if i == nil || !i.IsInt64() || reflect.Zero(typ).OverflowInt(i.Int64()) {
+			return nil, true, conversionError("", value, typ)
+		}
+
+		return reflect.ValueOf(i.Int64()).Convert(typ).Interface(), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i := truncate(value)
+		if i == nil || !i.IsUint64() || reflect.Zero(typ).OverflowUint(i.Uint64()) {
+			return nil, true, conversionError("", value, typ)
+		}
+
+		return reflect.ValueOf(i.Uint64()).Convert(typ).Interface(), true, nil
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(toFloat(value)).Convert(typ).Interface(), true, nil
+	case reflect.String:
+		s, _ := FormatBigNumber(value)
+		return s, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// truncate returns the integer part of a number, or nil if it is infinite.
+func truncate(value any) *big.Int {
+	if i, ok := value.(*big.Int); ok {
+		return i
+	}
+
+	r := toRat(value)
+	if r == nil {
+		return nil
+	}
+
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+func parseBig(s string, typ reflect.Type) (any, bool, error) {
+	var (
+		result any
+		ok     bool
+	)
+
+	switch typ {
+	case bigIntType:
+		result, ok = new(big.Int).SetString(s, 10)
+	case bigRatType:
+		result, ok = new(big.Rat).SetString(s)
+	default:
+		var err error
+		result, _, err = big.ParseFloat(s, 10, 64, big.ToNearestEven)
+		ok = err == nil
+	}
+
+	if !ok {
+		return nil, true, conversionError("", s, typ)
+	}
+
+	return result, true, nil
+}
@@ -0,0 +1,158 @@
+package values
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bigInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+func bigRat(s string) *big.Rat {
+	r, _ := new(big.Rat).SetString(s)
+	return r
+}
+
+// cents is a Decimal with two decimal places.
+type cents int64
+
+func (c cents) LiquidRat() *big.Rat { return big.NewRat(int64(c), 100) }
+
+func (c cents) LiquidFromRat(r *big.Rat) any {
+	n, _ := new(big.Rat).Mul(r, big.NewRat(100, 1)).Float64()
+	return cents(math.Round(n))
+}
+
+func (c cents) String() string {
+	return big.NewRat(int64(c), 100).FloatString(2)
+}
+
+func TestBigArithmetic(t *testing.T) {
+	huge := bigInt("100000000000000000000")
+
+	tests := []struct {
+		op       func(a, b any) (any, error)
+		a, b     any
+		expected string
+	}{
+		{BigAdd, huge, 1, "100000000000000000001"},
+		{BigAdd, 1, huge, "100000000000000000001"},
+		{BigSub, huge, uint64(math.MaxUint64), "81553255926290448385"},
+		{BigMul, huge, huge, "10000000000000000000000000000000000000000"},
+		{BigQuo, huge, -3, "-33333333333333333333"},
+		{BigAdd, big.NewRat(1, 3), 1, "4/3"},
+		{BigAdd, big.NewRat(1, 10), big.NewRat(1, 5), "3/10"},
+		{BigAdd, big.NewRat(1, 2), 0.25, "0.75"},
+		{BigMul, big.NewRat(1, 3), huge, "100000000000000000000/3"},
+		{BigQuo, big.NewRat(1, 3), 2, "1/6"},
+		{BigAdd, big.NewFloat(1.5), 1, "2.5"},
+		{BigMul, big.NewFloat(0.5), big.NewRat(1, 4), "0.125"},
+		{BigAdd, cents(1050), 0.25, "10.75"},
+		{BigMul, cents(199), 3, "5.97"},
+		{BigQuo, 10, cents(400), "2.50"},
+	}
+	for _, test := range tests {
+		actual, err := test.op(test.a, test.b)
+		require.NoError(t, err)
+
+		s, ok := FormatBigNumber(actual)
+		if !ok {
+			s = fmt.Sprint(actual)
+		}
+
+		require.Equalf(t, test.expected, s, "%v, %v", test.a, test.b)
+	}
+
+	// results keep the greater type
+	sum, _ := BigAdd(huge, 0.5)
+	require.IsType(t, float64(0), sum)
+	sum, _ = BigAdd(cents(1), big.NewFloat(1))
+	require.IsType(t, cents(0), sum)
+
+	_, err := BigQuo(huge, 0)
+	require.ErrorIs(t, err, ErrDivisionByZero)
+	_, err = BigQuo(huge, big.NewRat(0, 1))
+	require.ErrorIs(t, err, ErrDivisionByZero)
+	_, err = BigAdd(huge, "1")
+	require.Error(t, err)
+	_, err = BigQuo(huge, "2")
+	require.EqualError(t, err, "invalid divisor: '2'")
+	_, err = BigQuo(huge, nil)
+	require.EqualError(t, err, "invalid divisor: '<nil>'")
+}
+
+func TestBigRounding(t *testing.T) {
+	require.Equal(t, "7/2", BigRound(bigRat("3.45"), 1).(*big.Rat).RatString())
+	require.Equal(t, "-7/2", BigRound(bigRat("-3.45"), 1).(*big.Rat).RatString())
+	require.Equal(t, "4", BigRound(bigRat("3.5"), 0).(*big.Rat).RatString())
+	require.Equal(t, "1200", BigRound(bigInt("1234"), -2).(*big.Int).String())
+	require.Equal(t, cents(350), BigRound(cents(345), 1))
+	require.Equal(t, "3", BigFloor(bigRat("3.9")).(*big.Rat).RatString())
+	require.Equal(t, "-4", BigFloor(bigRat("-3.1")).(*big.Rat).RatString())
+	require.Equal(t, "4", BigCeil(big.NewFloat(3.1)).(*big.Float).String())
+	require.Equal(t, cents(400), BigCeil(cents(301)))
+	require.Equal(t, "5", BigAbs(bigInt("-5")).(*big.Int).String())
+}
+
+func TestBigComparison(t *testing.T) {
+	huge := bigInt("100000000000000000000")
+
+	require.True(t, Equal(bigInt("3"), 3))
+	require.True(t, Equal(3.0, bigInt("3")))
+	require.True(t, Equal(big.NewRat(1, 10), 0.1))
+	require.True(t, Equal(cents(10), 0.1))
+	require.True(t, Equal(cents(300), bigInt("3")))
+	require.False(t, Equal(huge, math.MaxInt64))
+	require.False(t, Equal(huge, "100000000000000000000"))
+
+	require.True(t, Less(math.MaxInt64, huge))
+	require.True(t, Less(big.NewRat(1, 3), 0.34))
+	require.True(t, Less(cents(99), 1))
+	require.True(t, Less(huge, math.Inf(1)))
+	require.False(t, Less(huge, "z"))
+	require.True(t, Comparable(huge, 1.5))
+	require.False(t, Comparable(huge, "z"))
+}
+
+func TestConvert_bigNumbers(t *testing.T) {
+	tests := []struct {
+		value    any
+		typ      reflect.Type
+		expected any
+	}{
+		{bigInt("12"), reflect.TypeOf(0), 12},
+		{bigInt("12"), reflect.TypeOf(uint8(0)), uint8(12)},
+		{big.NewRat(5, 2), reflect.TypeOf(0), 2},
+		{big.NewRat(5, 2), reflect.TypeOf(0.0), 2.5},
+		{cents(250), reflect.TypeOf(0.0), 2.5},
+		{bigInt("12"), reflect.TypeOf(""), "12"},
+		{cents(250), reflect.TypeOf(""), "2.50"},
+		{3, bigIntType, big.NewInt(3)},
+		{0.5, bigRatType, big.NewRat(1, 2)},
+		{"100000000000000000000", bigIntType, bigInt("100000000000000000000")},
+		{"1/3", bigRatType, big.NewRat(1, 3)},
+	}
+	for _, test := range tests {
+		actual, err := Convert(test.value, test.typ)
+		require.NoError(t, err)
+		require.Equalf(t, test.expected, actual, "%v → %s", test.value, test.typ)
+	}
+
+	_, err := Convert(bigInt("100000000000000000000"), reflect.TypeOf(0))
+	require.Error(t, err)
+	_, err = Convert(bigInt("300"), reflect.TypeOf(uint8(0)))
+	require.Error(t, err)
+	_, err = Convert("x", bigIntType)
+	require.Error(t, err)
+
+	f, err := Convert("1.25", bigFloatType)
+	require.NoError(t, err)
+	require.Equal(t, "1.25", f.(*big.Float).Text('g', -1))
+}
@@ -0,0 +1,155 @@
+package values
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// A CharacterMode determines what the size, first and last of a string, and the
+// string filters such as slice and truncate, count as a character.
+type CharacterMode int
+
+const (
+	// RuneCharacters counts Unicode code points, as Ruby does. This is the default.
+	RuneCharacters CharacterMode = iota
+	// GraphemeCharacters counts user-perceived characters: extended grapheme
+	// clusters, such as a letter with its combining accents, a flag, or an emoji
+	// ZWJ sequence. The segmentation follows the rules of Unicode Standard Annex
+	// #29, with approximate property tables.
+	GraphemeCharacters
+)
+
+// WithCharacterMode returns v, with the size, first and last properties of a
+// string counting characters according to m.
+func WithCharacterMode(v Value, m CharacterMode) Value {
+	switch sv := v.(type) {
+	case stringValue:
+		sv.chars = m
+		return sv
+	case customValue:
+		sv.base = WithCharacterMode(sv.base, m)
+		return sv
+	case *dropWrapper:
+		return WithCharacterMode(sv.Resolve(), m)
+	default:
+		return v
+	}
+}
+
+// Characters splits s into its characters.
+func (m CharacterMode) Characters(s string) []string {
+	chars := make([]string, 0, len(s))
+
+	for len(s) > 0 {
+		n := m.next(s)
+		chars = append(chars, s[:n])
+		s = s[n:]
+	}
+
+	return chars
+}
+
+// Count returns the number of characters in s.
+func (m CharacterMode) Count(s string) int {
+	if m == RuneCharacters {
+		return utf8.RuneCountInString(s)
+	}
+
+	count := 0
+	for ; len(s) > 0; count++ {
+		s = s[m.next(s):]
+	}
+
+	return count
+}
+
+// next returns the length in bytes of the first character of s.
+func (m CharacterMode) next(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if m == RuneCharacters {
+		return n
+	}
+
+	prev, riCount := r, 0
+	if isRegionalIndicator(r) {
+		riCount = 1
+	}
+
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		if graphemeBreak(prev, next, riCount) {
+			break
+		}
+
+		if isRegionalIndicator(next) {
+			riCount++
+		}
+
+		prev = next
+		n += size
+	}
+
+	return n
+}
+
+// graphemeBreak reports whether there is a grapheme cluster boundary between
+// prev and next. riCount is the number of regional indicators in the cluster.
+func graphemeBreak(prev, next rune, riCount int) bool {
+	switch {
+	case prev == '\r' && next == '\n': // GB3
+		return false
+	case isControl(prev) || isControl(next): // GB4, GB5
+		return true
+	case isHangulL(prev) && (isHangulL(next) || isHangulV(next) || isHangulLV(next) || isHangulLVT(next)): // GB6
+		return false
+	case (isHangulLV(prev) || isHangulV(prev)) && (isHangulV(next) || isHangulT(next)): // GB7
+		return false
+	case (isHangulLVT(prev) || isHangulT(prev)) && isHangulT(next): // GB8
+		return false
+	case isExtend(next) || next == zwj: // GB9
+		return false
+	case unicode.Is(unicode.Mc, next): // GB9a
+		return false
+	case prev == zwj && isPictographic(next): // GB11
+		return false
+	case isRegionalIndicator(prev) && isRegionalIndicator(next): // GB12, GB13
+		return riCount%2 == 0
+	default: // GB999
+		return true
+	}
+}
+
+const (
+	zwj  = '\u200d'
+	zwnj = '\u200c'
+)
+
+func isControl(r rune) bool {
+	return (unicode.IsControl(r) || unicode.In(r, unicode.Zl, unicode.Zp, unicode.Cf)) && !isExtend(r) && r != zwj
+}
+
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r == zwnj ||
+		r >= 0xfe00 && r <= 0xfe0f || // variation selectors
+		r >= 0x1f3fb && r <= 0x1f3ff || // emoji modifiers
+		r >= 0xe0020 && r <= 0xe007f || // tags
+		r >= 0xe0100 && r <= 0xe01ef // variation selectors supplement
+}
+
+func isPictographic(r rune) bool {
+	return r >= 0x1f000 && r <= 0x1faff || unicode.Is(unicode.So, r)
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1f1e6 && r <= 0x1f1ff }
+
+// Hangul jamo and syllables, for GB6-GB8.
+func isHangulL(r rune) bool { return r >= 0x1100 && r <= 0x115f || r >= 0xa960 && r <= 0xa97c }
+
+func isHangulV(r rune) bool { return r >= 0x1160 && r <= 0x11a7 || r >= 0xd7b0 && r <= 0xd7c6 }
+
+func isHangulT(r rune) bool { return r >= 0x11a8 && r <= 0x11ff || r >= 0xd7cb && r <= 0xd7fb }
+
+func isHangulLV(r rune) bool { return r >= 0xac00 && r <= 0xd7a3 && (r-0xac00)%28 == 0 }
+
+func isHangulLVT(r rune) bool { return r >= 0xac00 && r <= 0xd7a3 && (r-0xac00)%28 != 0 }
@@ -0,0 +1,38 @@
+package values
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharacterMode(t *testing.T) {
+	tests := []struct {
+		in               string
+		runes, graphemes []string
+	}{
+		{"", []string{}, []string{}},
+		{"héllo", []string{"h", "é", "l", "l", "o"}, []string{"h", "é", "l", "l", "o"}},
+		{"e\u0301a", []string{"e", "\u0301", "a"}, []string{"e\u0301", "a"}},
+		{"a\r\nb", []string{"a", "\r", "\n", "b"}, []string{"a", "\r\n", "b"}},
+		// Hangul jamo compose a syllable
+		{"\u1100\u1161\u11a8", []string{"\u1100", "\u1161", "\u11a8"}, []string{"\u1100\u1161\u11a8"}},
+		// regional indicators pair up into flags
+		{"\U0001F1EB\U0001F1F7\U0001F1E9", []string{"\U0001F1EB", "\U0001F1F7", "\U0001F1E9"}, []string{"\U0001F1EB\U0001F1F7", "\U0001F1E9"}},
+		// an emoji with a skin tone modifier, and a ZWJ sequence
+		{"\U0001F44D\U0001F3FD!", []string{"\U0001F44D", "\U0001F3FD", "!"}, []string{"\U0001F44D\U0001F3FD", "!"}},
+		{"❤\ufe0f\u200d\U0001F525", []string{"❤", "\ufe0f", "\u200d", "\U0001F525"}, []string{"❤\ufe0f\u200d\U0001F525"}},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.runes, RuneCharacters.Characters(test.in), test.in)
+		require.Equal(t, len(test.runes), RuneCharacters.Count(test.in), test.in)
+		require.Equal(t, test.graphemes, GraphemeCharacters.Characters(test.in), test.in)
+		require.Equal(t, len(test.graphemes), GraphemeCharacters.Count(test.in), test.in)
+	}
+
+	// the size, first and last properties of a string
+	v := WithCharacterMode(ValueOf("e\u0301te\u0301"), GraphemeCharacters)
+	require.Equal(t, 3, v.PropertyValue(ValueOf("size")).Interface())
+	require.Equal(t, "e\u0301", v.PropertyValue(ValueOf("first")).Interface())
+	require.Equal(t, 5, ValueOf("e\u0301te\u0301").PropertyValue(ValueOf("size")).Interface())
+}
@@ -12,6 +12,15 @@ var (
 // Equal returns a bool indicating whether a == b after conversion.
 func Equal(a, b any) bool { //nolint: gocyclo
 	a, b = ToLiquid(a), ToLiquid(b)
+	if n, ok := compareCustom(a, b); ok {
+		return n == 0
+	}
+
+	if IsBigNumber(a) || IsBigNumber(b) {
+		n, ok := compareBig(a, b)
+		return ok && n == 0
+	}
+
 	if a == nil || b == nil {
 		return a == b
 	}
@@ -52,6 +61,15 @@ func Equal(a, b any) bool { //nolint: gocyclo
 // Less returns a bool indicating whether a < b.
 func Less(a, b any) bool {
 	a, b = ToLiquid(a), ToLiquid(b)
+	if n, ok := compareCustom(a, b); ok {
+		return n < 0
+	}
+
+	if IsBigNumber(a) || IsBigNumber(b) {
+		n, ok := compareBig(a, b)
+		return ok && n < 0
+	}
+
 	if a == nil || b == nil {
 		return false
 	}
@@ -71,6 +89,24 @@ func Less(a, b any) bool {
 	}
 }
 
+// compareCustom compares a and b with the LiquidCompare method of either of them.
+// The second result is false if neither implements it, or neither can compare them.
+func compareCustom(a, b any) (int, bool) {
+	if c, ok := a.(comparer); ok {
+		if n, ok := c.LiquidCompare(b); ok {
+			return n, true
+		}
+	}
+
+	if c, ok := b.(comparer); ok {
+		if n, ok := c.LiquidCompare(a); ok {
+			return -n, true
+		}
+	}
+
+	return 0, false
+}
+
 func joinKind(a, b reflect.Kind) reflect.Kind { //nolint: gocyclo
 	if a == b {
 		return a
@@ -107,6 +143,15 @@ func isIntKind(k reflect.Kind) bool {
 	}
 }
 
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
 func isFloatKind(k reflect.Kind) bool {
 	switch k {
 	case reflect.Float32, reflect.Float64:
@@ -115,3 +160,30 @@ func isFloatKind(k reflect.Kind) bool {
 		return false
 	}
 }
+
+// Comparable returns a bool indicating whether Less can order a and b.
+// If it can't, Less(a, b) and Less(b, a) are both false.
+func Comparable(a, b any) bool {
+	a, b = ToLiquid(a), ToLiquid(b)
+	if _, ok := compareCustom(a, b); ok {
+		return true
+	}
+
+	if IsBigNumber(a) || IsBigNumber(b) {
+		_, ok := compareBig(a, b)
+		return ok
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	switch joinKind(reflect.ValueOf(a).Kind(), reflect.ValueOf(b).Kind()) {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
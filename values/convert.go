@@ -89,6 +89,12 @@ func convertValueToFloat(value any, typ reflect.Type) (float64, error) {
 func Convert(value any, typ reflect.Type) (any, error) { //nolint: gocyclo
 	value = ToLiquid(value)
 	rv := reflect.ValueOf(value)
+
+	// before the test below, which would convert a Decimal's underlying value
+	if v, ok, err := convertBig(value, typ); ok {
+		return v, err
+	}
+
 	// int.Convert(string) returns "\x01" not "1", so guard against that in the following test
 	if typ.Kind() != reflect.String && value != nil && rv.Type().ConvertibleTo(typ) {
 		return rv.Convert(typ).Interface(), nil
@@ -233,6 +239,8 @@ func Convert(value any, typ reflect.Type) (any, error) { //nolint: gocyclo
 			return result.Interface(), nil
 		} else if r, ok := value.(Range); ok {
 			return r.AsArray(), nil
+		} else if items, ok := Items(value); ok {
+			return Convert(items, typ)
 		}
 
 		switch rv.Kind() {
@@ -5,4 +5,41 @@
 // Since the intent is to provide runtime services for the Liquid expression interpreter,
 // this package does not implement "generic" generics.
 // It attempts to implement Liquid semantics (which are largely Ruby semantics).
+//
+// # Properties and Indices
+//
+// Value.PropertyValue implements x.name, and Value.IndexValue implements x[key].
+// They follow the rules of Shopify Liquid's VariableLookup and
+// Context#lookup_and_evaluate, where size, first and last are commands that only
+// apply to property syntax:
+//
+//	value          x.name     x[key]          x.size            x.first              x.last
+//	-------------  ---------  --------------  ----------------  -------------------  -------------
+//	array, slice   nil        element (1)     length            first element        last element
+//	Range          nil        element (1)     length            start                end
+//	map, MapSlice  entry (2)  entry           entries (3)       [key, value] (3, 4)  entry (2)
+//	string         nil        nil             characters (5)    character (5)        character (5)
+//	struct         field (6)  same as x.name  field or Len (3)  field (6)            field (6)
+//	other          nil        nil             nil               nil                  nil
+//
+// (1) A number selects an element. A negative index counts from the end, and, as
+// in Ruby, a float index is truncated toward zero: a[1.9] is a[1], and a[-1.5] is
+// a[-1]. An index outside the sequence, NaN, and any other key select nil.
+//
+// (2) The entry for the key name, or nil. A map has no last command.
+//
+// (3) An entry, field or method with this name takes precedence.
+//
+// (4) A MapSlice is ordered, so this is its first item. A Go map isn't, so this is
+// the entry with the least key.
+//
+// (5) Counted according to the CharacterMode. The first and last characters of an
+// empty string are "".
+//
+// (6) A field or method, named by the StructNaming. A method, or a func-valued
+// field, is called.
+//
+// A value that implements the custom value interfaces of the liquid package, such
+// as PropertyGetter and Indexer, overrides these rules for the operations that it
+// implements. A drop is looked up as the value of its ToLiquid method.
 package values
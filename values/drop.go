@@ -2,6 +2,7 @@ package values
 
 import (
 	"sync"
+	"time"
 )
 
 type drop interface {
@@ -21,12 +22,31 @@ func ToLiquid(value any) any {
 type dropWrapper struct {
 	sync.Once
 
-	d drop
-	v Value
+	d    drop
+	v    Value
+	hook func(drop any, elapsed time.Duration)
+}
+
+// TraceDrop returns v. If v is a drop that hasn't been resolved yet, hook is called
+// with the drop and the duration of its ToLiquid method when it is.
+func TraceDrop(v Value, hook func(drop any, elapsed time.Duration)) Value {
+	if w, ok := v.(*dropWrapper); ok && hook != nil {
+		w.hook = hook
+	}
+
+	return v
 }
 
 func (w *dropWrapper) Resolve() Value {
-	w.Do(func() { w.v = ValueOf(w.d.ToLiquid()) })
+	w.Do(func() {
+		start := time.Now()
+		w.v = ValueOf(w.d.ToLiquid())
+
+		if w.hook != nil {
+			w.hook(w.d, time.Since(start))
+		}
+	})
+
 	return w.v
 }
 
@@ -2,6 +2,7 @@ package values
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +24,21 @@ func TestValue_drop(t *testing.T) {
 	require.Equal(t, 7, dv.PropertyValue(ValueOf("size")).Interface())
 }
 
+func TestTraceDrop(t *testing.T) {
+	var traced []any
+
+	hook := func(drop any, _ time.Duration) { traced = append(traced, drop) }
+
+	d := TraceDrop(ValueOf(testDrop{1}), hook)
+	require.Empty(t, traced)
+	require.Equal(t, 1, d.Int())
+	require.Equal(t, 1, d.Int())
+	require.Equal(t, []any{testDrop{1}}, traced)
+
+	require.Equal(t, 2, TraceDrop(ValueOf(2), hook).Int())
+	require.Len(t, traced, 1)
+}
+
 func TestDrop_Resolve_race(t *testing.T) {
 	d := ValueOf(testDrop{1})
 	values := make(chan int, 2)
@@ -0,0 +1,171 @@
+package values
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// A FieldNamer returns the name by which templates access a struct field or
+// method, or "" to hide it. For a method, only the Name and Type of the field are set.
+//
+// A `liquid:"name"` tag takes precedence over the namer.
+type FieldNamer func(field reflect.StructField) string
+
+// ExactFieldName names a field or method by its Go name. This is the default.
+func ExactFieldName(field reflect.StructField) string { return field.Name }
+
+// SnakeCaseFieldName names a field or method in snake_case: CompareAtPrice as
+// compare_at_price, and HTMLTitle as html_title. Adjacent initialisms aren't
+// separated: HTMLURL is htmlurl, unless a `liquid:"html_url"` tag names it.
+func SnakeCaseFieldName(field reflect.StructField) string {
+	runes := []rune(field.Name)
+
+	var sb strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				sb.WriteByte('_')
+			}
+		}
+
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
+
+// CamelCaseFieldName names a field or method in camelCase: CompareAtPrice as
+// compareAtPrice, and HTMLURL as htmlurl.
+func CamelCaseFieldName(field reflect.StructField) string {
+	runes := []rune(field.Name)
+
+	for i, r := range runes {
+		// In an initialism that is followed by a word, as in HTMLTitle, the last
+		// capital starts the word.
+		if !unicode.IsUpper(r) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(r)
+	}
+
+	return string(runes)
+}
+
+// JSONFieldName names a field by the name in its `json:"name"` tag, if it has
+// one, and otherwise by its Go name. A field tagged `json:"-"` is hidden.
+func JSONFieldName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// A StructNaming resolves the property names that templates use to the fields
+// and methods of structs. It caches the names of each struct type.
+type StructNaming struct {
+	namer FieldNamer
+	cache sync.Map // reflect.Type -> map[string]structMember
+}
+
+// A structMember is a field or method of a struct type.
+type structMember struct {
+	method int   // the method index, or -1 for a field
+	field  []int // the field index sequence, for reflect.Value.FieldByIndex
+}
+
+// NewStructNaming returns a StructNaming that names fields and methods with
+// namer. If namer is nil, it uses ExactFieldName.
+func NewStructNaming(namer FieldNamer) *StructNaming {
+	if namer == nil {
+		namer = ExactFieldName
+	}
+
+	return &StructNaming{namer: namer}
+}
+
+// defaultStructNaming is the StructNaming of values that don't have another.
+var defaultStructNaming = NewStructNaming(nil)
+
+// WithStructNaming returns v, with its struct properties resolved by n, if it
+// is a struct or a pointer to one.
+func WithStructNaming(v Value, n *StructNaming) Value {
+	switch sv := v.(type) {
+	case structValue:
+		sv.naming = n
+		return sv
+	case customValue:
+		sv.base = WithStructNaming(sv.base, n)
+		return sv
+	case *dropWrapper:
+		return WithStructNaming(sv.Resolve(), n)
+	default:
+		return v
+	}
+}
+
+// lookup returns the member of the struct or struct pointer type t that templates
+// access as name.
+func (n *StructNaming) lookup(t reflect.Type, name string) (structMember, bool) {
+	members, ok := n.cache.Load(t)
+	if !ok {
+		members, _ = n.cache.LoadOrStore(t, n.members(t))
+	}
+
+	m, ok := members.(map[string]structMember)[name]
+
+	return m, ok
+}
+
+// members returns the members of t by name. Methods take precedence over fields.
+func (n *StructNaming) members(t reflect.Type) map[string]structMember {
+	members := map[string]structMember{}
+
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	for _, field := range reflect.VisibleFields(st) {
+		if !field.IsExported() {
+			continue
+		}
+
+		if name := n.name(field); name != "" {
+			members[name] = structMember{-1, field.Index}
+		}
+	}
+
+	for i := range t.NumMethod() {
+		m := t.Method(i)
+		if name := n.namer(reflect.StructField{Name: m.Name, Type: m.Type}); name != "" {
+			members[name] = structMember{i, nil}
+		}
+	}
+
+	return members
+}
+
+func (n *StructNaming) name(field reflect.StructField) string {
+	if name, ok := field.Tag.Lookup(tagKey); ok {
+		return name
+	}
+
+	return n.namer(field)
+}
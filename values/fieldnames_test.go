@@ -0,0 +1,92 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldNamers(t *testing.T) {
+	tests := []struct{ name, snake, camel string }{
+		{"Title", "title", "title"},
+		{"CompareAtPrice", "compare_at_price", "compareAtPrice"},
+		{"ID", "id", "id"},
+		{"UserID", "user_id", "userID"},
+		{"HTMLTitle", "html_title", "htmlTitle"},
+		{"HTMLURL", "htmlurl", "htmlurl"},
+		{"Line2Address", "line2_address", "line2Address"},
+	}
+	for _, test := range tests {
+		field := reflect.StructField{Name: test.name}
+		require.Equal(t, test.snake, SnakeCaseFieldName(field), test.name)
+		require.Equal(t, test.camel, CamelCaseFieldName(field), test.name)
+		require.Equal(t, test.name, ExactFieldName(field), test.name)
+	}
+
+	type tagged struct {
+		A int `json:"a_name,omitempty"`
+		B int `json:",omitempty"`
+		C int `json:"-"`
+		D int
+	}
+
+	st := reflect.TypeOf(tagged{})
+	require.Equal(t, "a_name", JSONFieldName(st.Field(0)))
+	require.Equal(t, "B", JSONFieldName(st.Field(1)))
+	require.Equal(t, "", JSONFieldName(st.Field(2)))
+	require.Equal(t, "D", JSONFieldName(st.Field(3)))
+}
+
+type namingBase struct {
+	BaseName string
+}
+
+type namingProduct struct {
+	*namingBase
+
+	CompareAtPrice int
+	Handle         string `json:"slug" liquid:"handle"`
+	Vendor         string `json:"-"`
+	hidden         int
+}
+
+func (p namingProduct) FullTitle() string { return "Full " + p.Handle }
+
+func TestStructNaming(t *testing.T) {
+	product := namingProduct{&namingBase{"base"}, 10, "hat", "acme", 0}
+	get := func(naming *StructNaming, v any, name string) any {
+		return WithStructNaming(ValueOf(v), naming).PropertyValue(ValueOf(name)).Interface()
+	}
+
+	snake := NewStructNaming(SnakeCaseFieldName)
+	require.Equal(t, 10, get(snake, product, "compare_at_price"))
+	require.Equal(t, 10, get(snake, &product, "compare_at_price"))
+	require.Nil(t, get(snake, product, "CompareAtPrice"))
+	require.Equal(t, "hat", get(snake, product, "handle"))
+	require.Equal(t, "Full hat", get(snake, product, "full_title"))
+	require.Equal(t, "base", get(snake, product, "base_name"))
+	require.True(t, WithStructNaming(ValueOf(product), snake).Contains(ValueOf("vendor")))
+
+	jsonNaming := NewStructNaming(JSONFieldName)
+	require.Equal(t, 10, get(jsonNaming, product, "CompareAtPrice"))
+	require.Equal(t, "hat", get(jsonNaming, product, "handle"))
+	require.Nil(t, get(jsonNaming, product, "slug"))
+	require.Nil(t, get(jsonNaming, product, "Vendor"))
+	require.Equal(t, "Full hat", get(jsonNaming, product, "FullTitle"))
+
+	// the default naming
+	require.Equal(t, 10, get(nil, product, "CompareAtPrice"))
+	require.Equal(t, "base", get(nil, product, "BaseName"))
+	require.Nil(t, get(nil, product, "hidden"))
+	require.Nil(t, get(nil, namingProduct{}, "BaseName"))
+}
+
+func BenchmarkStructValue_PropertyValue(b *testing.B) {
+	v := ValueOf(namingProduct{CompareAtPrice: 10, Handle: "hat"})
+	name := ValueOf("handle")
+
+	for range b.N {
+		v.PropertyValue(name)
+	}
+}
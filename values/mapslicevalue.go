@@ -37,9 +37,18 @@ func (v mapSliceValue) IndexValue(index Value) Value {
 
 func (v mapSliceValue) PropertyValue(index Value) Value {
 	result := v.IndexValue(index)
-	if result == nilValue && index.Interface() == sizeKey {
-		result = ValueOf(len(v.slice))
+	if result != nilValue {
+		return result
 	}
 
-	return result
+	switch index.Interface() {
+	case sizeKey:
+		return ValueOf(len(v.slice))
+	case firstKey:
+		if len(v.slice) > 0 {
+			return ValueOf([]any{v.slice[0].Key, v.slice[0].Value})
+		}
+	}
+
+	return nilValue
 }
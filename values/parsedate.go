@@ -44,14 +44,22 @@ var dateLayouts = []string{
 	"Jan 2 2006",
 }
 
-// ParseDate tries a few heuristics to parse a date from a string
+// ParseDate tries a few heuristics to parse a date from a string. The strings
+// "now" and "today" are the current time. A date without a time zone is in the
+// local time zone.
 func ParseDate(s string) (time.Time, error) {
-	if s == "now" {
+	if s == "now" || s == "today" {
 		return time.Now(), nil
 	}
 
+	return ParseDateInLocation(s, time.Local)
+}
+
+// ParseDateInLocation is like ParseDate, except that it parses a date without a
+// time zone in loc, and doesn't recognize "now" and "today".
+func ParseDateInLocation(s string, loc *time.Location) (time.Time, error) {
 	for _, layout := range dateLayouts {
-		t, err := time.ParseInLocation(layout, s, time.Local)
+		t, err := time.ParseInLocation(layout, s, loc)
 		if err == nil {
 			return t, nil
 		}
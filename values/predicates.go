@@ -11,6 +11,10 @@ func IsEmpty(value any) bool {
 		return false
 	}
 
+	if s, ok := value.(sizer); ok {
+		return s.LiquidSize() == 0
+	}
+
 	r := reflect.ValueOf(value)
 	switch r.Kind() {
 	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
@@ -21,3 +25,29 @@ func IsEmpty(value any) bool {
 		return false
 	}
 }
+
+// IsTrue returns a bool indicating whether the value is true in a condition. In keeping
+// with Liquid semantics, every value except nil and false is true, unless it implements
+// the Truthy interface of the liquid package.
+func IsTrue(value any) bool {
+	if t, ok := value.(truthy); ok {
+		return t.LiquidTruthy()
+	}
+
+	return value != nil && value != false
+}
+
+// IsLiquidType returns a bool indicating whether templates can make use of the value.
+// Functions, channels, complex numbers, and unsafe pointers are not Liquid types.
+func IsLiquidType(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,154 @@
+package values
+
+// These are the methods of the liquid package's PropertyGetter, Indexer, Sizer,
+// Iterable, Comparer, Truthy, Container and MethodMissing interfaces, which
+// document them.
+type (
+	propertyGetter interface {
+		LiquidProperty(name string) (any, bool)
+	}
+	indexer interface {
+		LiquidIndex(index any) (any, bool)
+	}
+	sizer interface {
+		LiquidSize() int
+	}
+	iterable interface {
+		LiquidEach(yield func(any) bool)
+	}
+	comparer interface {
+		LiquidCompare(other any) (int, bool)
+	}
+	truthy interface {
+		LiquidTruthy() bool
+	}
+	container interface {
+		LiquidContains(value any) bool
+	}
+	methodMissing interface {
+		LiquidMethodMissing(name string) any
+	}
+)
+
+// isCustom returns a bool indicating whether value implements any of the custom value interfaces.
+func isCustom(value any) bool {
+	switch value.(type) {
+	case propertyGetter, indexer, sizer, iterable, comparer, truthy, container, methodMissing:
+		return true
+	default:
+		return false
+	}
+}
+
+// Items returns the elements of a value that implements the Iterable interface.
+// The second result is false if it doesn't implement it.
+func Items(value any) ([]any, bool) {
+	it, ok := value.(iterable)
+	if !ok {
+		return nil, false
+	}
+
+	items := []any{}
+
+	it.LiquidEach(func(item any) bool {
+		items = append(items, item)
+		return true
+	})
+
+	return items, true
+}
+
+// A customValue is a Go value that implements some of the custom value interfaces.
+// The methods of the interfaces that it doesn't implement, and the properties and
+// indices that its methods don't handle, fall back to base.
+type customValue struct {
+	value any
+	base  Value // the Value of an otherwise identical type without the interfaces
+}
+
+func (v customValue) Interface() any         { return v.value }
+func (v customValue) Int() int               { return v.base.Int() }
+func (v customValue) Equal(other Value) bool { return Equal(v.value, other.Interface()) }
+func (v customValue) Less(other Value) bool  { return Less(v.value, other.Interface()) }
+
+func (v customValue) Contains(elem Value) bool {
+	if c, ok := v.value.(container); ok {
+		return c.LiquidContains(elem.Interface())
+	}
+
+	return v.base.Contains(elem)
+}
+
+func (v customValue) IndexValue(iv Value) Value {
+	if x, ok := v.value.(indexer); ok {
+		if r, ok := x.LiquidIndex(iv.Interface()); ok {
+			return ValueOf(r)
+		}
+	}
+
+	// As in Ruby, obj["name"] is the same as obj.name.
+	if _, ok := iv.Interface().(string); ok {
+		return v.PropertyValue(iv)
+	}
+
+	return v.base.IndexValue(iv)
+}
+
+func (v customValue) PropertyValue(iv Value) Value {
+	name, ok := iv.Interface().(string)
+	if !ok {
+		return v.base.PropertyValue(iv)
+	}
+
+	if r, ok := v.property(name); ok {
+		return ValueOf(r)
+	}
+
+	r := v.base.PropertyValue(iv)
+	if m, ok := v.value.(methodMissing); ok && r.Interface() == nil {
+		return ValueOf(m.LiquidMethodMissing(name))
+	}
+
+	return r
+}
+
+func (v customValue) Test() bool {
+	if t, ok := v.value.(truthy); ok {
+		return t.LiquidTruthy()
+	}
+
+	return v.base.Test()
+}
+
+// property returns the named property. It also implements size, first and last
+// for values that implement Sizer or Iterable but don't define these properties.
+func (v customValue) property(name string) (any, bool) {
+	if g, ok := v.value.(propertyGetter); ok {
+		if r, ok := g.LiquidProperty(name); ok {
+			return r, true
+		}
+	}
+
+	switch name {
+	case sizeKey:
+		if s, ok := v.value.(sizer); ok {
+			return s.LiquidSize(), true
+		}
+	case firstKey, lastKey:
+		it, ok := v.value.(iterable)
+		if !ok {
+			break
+		}
+
+		var r any
+
+		it.LiquidEach(func(item any) bool {
+			r = item
+			return name == lastKey
+		})
+
+		return r, true
+	}
+
+	return nil, false
+}
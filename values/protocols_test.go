@@ -0,0 +1,92 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A bag implements all the custom value interfaces.
+type bag struct{ items []any }
+
+func (b bag) LiquidProperty(name string) (any, bool) {
+	if name == "kind" {
+		return "bag", true
+	}
+
+	return nil, false
+}
+
+func (b bag) LiquidIndex(index any) (any, bool) {
+	if i, ok := index.(int); ok && 0 <= i && i < len(b.items) {
+		return b.items[i], true
+	}
+
+	return nil, false
+}
+
+func (b bag) LiquidSize() int { return len(b.items) }
+
+func (b bag) LiquidEach(yield func(any) bool) {
+	for _, item := range b.items {
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+func (b bag) LiquidCompare(other any) (int, bool) {
+	if n, ok := other.(int); ok {
+		return len(b.items) - n, true
+	}
+
+	return 0, false
+}
+
+func (b bag) LiquidTruthy() bool { return len(b.items) > 0 }
+
+func (b bag) LiquidContains(value any) bool {
+	for _, item := range b.items {
+		if Equal(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestCustomValue(t *testing.T) {
+	full, empty := bag{[]any{"a", "b"}}, bag{}
+	v := ValueOf(full)
+
+	require.Equal(t, full, v.Interface())
+	require.Equal(t, "bag", v.PropertyValue(ValueOf("kind")).Interface())
+	require.Equal(t, "bag", v.IndexValue(ValueOf("kind")).Interface())
+	require.Equal(t, "b", v.IndexValue(ValueOf(1)).Interface())
+	require.Nil(t, v.IndexValue(ValueOf(2)).Interface())
+	require.Equal(t, 2, v.PropertyValue(ValueOf("size")).Interface())
+	require.Equal(t, "a", v.PropertyValue(ValueOf("first")).Interface())
+	require.Equal(t, "b", v.PropertyValue(ValueOf("last")).Interface())
+	require.Nil(t, v.PropertyValue(ValueOf("missing")).Interface())
+	require.True(t, v.Contains(ValueOf("a")))
+	require.False(t, v.Contains(ValueOf("c")))
+	require.True(t, v.Test())
+	require.False(t, ValueOf(empty).Test())
+
+	require.True(t, Equal(full, 2))
+	require.True(t, Equal(2, full))
+	require.False(t, Equal(full, "2"))
+	require.True(t, Less(full, 3))
+	require.True(t, Less(1, full))
+	require.True(t, Comparable(1, full))
+
+	require.Equal(t, 2, Length(full))
+	require.True(t, IsEmpty(empty))
+	require.True(t, IsTrue(full))
+	require.False(t, IsTrue(empty))
+
+	a, err := Convert(full, reflect.TypeOf([]string{}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, a)
+}
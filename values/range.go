@@ -1,6 +1,16 @@
 package values
 
-// A Range is the range of integers from b to e inclusive.
+import (
+	"fmt"
+	"math"
+)
+
+// A Range is the range of integers from b to e inclusive, as in (1..5).
+//
+// It is a value in its own right: it renders as 1..5, its first, last and size
+// properties and the contains operator take constant time, and it is only
+// converted to an array of its elements by the filters that need one, such as
+// join and reverse.
 type Range struct {
 	b, e int
 }
@@ -10,12 +20,23 @@ func NewRange(b, e int) Range {
 	return Range{b, e}
 }
 
+// String returns the range as Ruby writes it, for example "1..5".
+func (r Range) String() string { return fmt.Sprintf("%d..%d", r.b, r.e) }
+
 // Len is in the iteration interface
-func (r Range) Len() int { return r.e + 1 - r.b }
+func (r Range) Len() int { return max(0, r.e+1-r.b) }
 
 // Index is in the iteration interface
 func (r Range) Index(i int) any { return r.b + i }
 
+// First returns the first element of the range. As in Ruby, this is the start
+// of the range, even if the range is empty.
+func (r Range) First() int { return r.b }
+
+// Last returns the last element of the range. As in Ruby, this is the end of the
+// range, even if the range is empty.
+func (r Range) Last() int { return r.e }
+
 // AsArray converts the range into an array.
 func (r Range) AsArray() []any {
 	a := make([]any, 0, r.Len())
@@ -25,3 +46,66 @@ func (r Range) AsArray() []any {
 
 	return a
 }
+
+// The following methods implement the custom value interfaces, which ValueOf
+// uses to make a Range a Value.
+
+// LiquidProperty implements first and last.
+func (r Range) LiquidProperty(name string) (any, bool) {
+	switch name {
+	case firstKey:
+		return r.First(), true
+	case lastKey:
+		return r.Last(), true
+	default:
+		return nil, false
+	}
+}
+
+// LiquidIndex returns the element at an index, as for an array. A negative index
+// counts from the end of the range.
+func (r Range) LiquidIndex(index any) (any, bool) {
+	if i, ok := sequenceIndex(index, r.Len()); ok {
+		return r.Index(i), true
+	}
+
+	return nil, true
+}
+
+// LiquidSize returns the number of elements.
+func (r Range) LiquidSize() int { return r.Len() }
+
+// LiquidEach yields the elements, without converting the range to an array.
+func (r Range) LiquidEach(yield func(any) bool) {
+	for i := r.b; i <= r.e; i++ {
+		if !yield(i) {
+			return
+		}
+	}
+}
+
+// LiquidContains reports whether a number is within the range.
+func (r Range) LiquidContains(value any) bool {
+	switch kindOfNumber(value) {
+	case intNumber, floatNumber:
+		f := toFloat(value)
+		return !math.IsNaN(f) && float64(r.b) <= f && f <= float64(r.e)
+	case notNumber:
+		return false
+	default:
+		n, ok := compareBig(r.b, value)
+		m, ok2 := compareBig(value, r.e)
+
+		return ok && ok2 && n <= 0 && m <= 0
+	}
+}
+
+// LiquidCompare reports whether the range is equal to another range. Ranges
+// aren't ordered.
+func (r Range) LiquidCompare(other any) (int, bool) {
+	if o, ok := other.(Range); ok && o == r {
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,44 @@
+package values
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRange(t *testing.T) {
+	r := NewRange(1, 5)
+	v := ValueOf(r)
+
+	require.Equal(t, "1..5", r.String())
+	require.Equal(t, 1, v.PropertyValue(ValueOf("first")).Interface())
+	require.Equal(t, 5, v.PropertyValue(ValueOf("last")).Interface())
+	require.Equal(t, 5, v.PropertyValue(ValueOf("size")).Interface())
+	require.Equal(t, 2, v.IndexValue(ValueOf(1)).Interface())
+	require.Equal(t, 5, v.IndexValue(ValueOf(-1)).Interface())
+	require.Nil(t, v.IndexValue(ValueOf(5)).Interface())
+
+	require.True(t, v.Contains(ValueOf(1)))
+	require.True(t, v.Contains(ValueOf(5)))
+	require.True(t, v.Contains(ValueOf(2.5)))
+	require.True(t, v.Contains(ValueOf(big.NewInt(3))))
+	require.False(t, v.Contains(ValueOf(6)))
+	require.False(t, v.Contains(ValueOf("3")))
+
+	require.True(t, v.Equal(ValueOf(NewRange(1, 5))))
+	require.False(t, v.Equal(ValueOf(NewRange(1, 4))))
+	require.False(t, v.Equal(ValueOf([]any{1, 2, 3, 4, 5})))
+
+	// an empty range
+	empty := ValueOf(NewRange(5, 1))
+	require.Equal(t, 0, Length(NewRange(5, 1)))
+	require.Equal(t, 5, empty.PropertyValue(ValueOf("first")).Interface())
+	require.False(t, empty.Contains(ValueOf(3)))
+	require.Equal(t, []any{}, NewRange(5, 1).AsArray())
+
+	// a large range isn't converted to an array
+	huge := ValueOf(NewRange(1, 1<<60))
+	require.Equal(t, 1<<60, huge.PropertyValue(ValueOf("last")).Interface())
+	require.True(t, huge.Contains(ValueOf(1<<59)))
+}
@@ -29,13 +29,21 @@ func (s genericSortable) Less(i, j int) bool {
 
 // SortByProperty sorts maps on their key indices.
 func SortByProperty(data []any, key string, nilFirst bool) {
-	sort.Sort(sortableByProperty{data, key, nilFirst})
+	sort.Sort(sortableByProperty{data, key, nilFirst, nil})
+}
+
+// SortByPropertyValue sorts maps on their key indices, and other values, such as
+// structs, on their key properties. valueOf returns the Value whose property is used;
+// pass one that applies a StructNaming, for struct properties to be named by it.
+func SortByPropertyValue(data []any, key string, nilFirst bool, valueOf func(any) Value) {
+	sort.Sort(sortableByProperty{data, key, nilFirst, valueOf})
 }
 
 type sortableByProperty struct {
 	data     []any
 	key      string
 	nilFirst bool
+	valueOf  func(any) Value // if nil, only maps have properties
 }
 
 // Len is part of sort.Interface.
@@ -51,7 +59,8 @@ func (s sortableByProperty) Swap(i, j int) {
 
 // Less is part of sort.Interface.
 func (s sortableByProperty) Less(i, j int) bool {
-	// index returns the value at s.key, if in is a map that contains this key
+	// index returns the value at s.key, if in is a map that contains this key,
+	// or else its s.key property, if s.valueOf is set
 	index := func(i int) any {
 		value := ToLiquid(s.data[i])
 
@@ -61,6 +70,8 @@ func (s sortableByProperty) Less(i, j int) bool {
 			if elem.IsValid() {
 				return elem.Interface()
 			}
+		} else if s.valueOf != nil && value != nil {
+			return s.valueOf(value).PropertyValue(ValueOf(s.key)).Interface()
 		}
 
 		return nil
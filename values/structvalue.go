@@ -1,10 +1,33 @@
 package values
 
 import (
+	"fmt"
 	"reflect"
 )
 
-type structValue struct{ wrapperValue }
+// A MethodPanic records a panic in a method, or func-valued field, that implements
+// a struct property. See WithMethodPanics.
+type MethodPanic struct {
+	Name  string // the property name
+	Value any    // the value that was passed to panic
+}
+
+func (e MethodPanic) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Name, e.Value)
+}
+
+// A lengther is a type with Go's conventional length method. Unless a struct has
+// a size property, its size is its Len.
+type lengther interface {
+	Len() int
+}
+
+type structValue struct {
+	wrapperValue
+
+	naming       *StructNaming // if nil, defaultStructNaming
+	methodPanics bool          // whether a panic in a method is re-panicked as a MethodPanic
+}
 
 func (sv structValue) IndexValue(index Value) Value {
 	return sv.PropertyValue(index)
@@ -16,24 +39,9 @@ func (sv structValue) Contains(elem Value) bool {
 		return false
 	}
 
-	st := reflect.TypeOf(sv.value)
-	if st.Kind() == reflect.Ptr {
-		if _, found := st.MethodByName(name); found {
-			return true
-		}
-
-		st = st.Elem()
-	}
+	_, found := sv.lookup(name)
 
-	if _, found := st.MethodByName(name); found {
-		return true
-	}
-
-	if _, found := sv.findField(name); found {
-		return true
-	}
-
-	return false
+	return found
 }
 
 func (sv structValue) PropertyValue(index Value) Value {
@@ -42,66 +50,51 @@ func (sv structValue) PropertyValue(index Value) Value {
 		return nilValue
 	}
 
-	sr := reflect.ValueOf(sv.value)
-
-	st := reflect.TypeOf(sv.value)
-	if st.Kind() == reflect.Ptr {
-		if _, found := st.MethodByName(name); found {
-			m := sr.MethodByName(name)
-			return sv.invoke(m)
+	m, ok := sv.lookup(name)
+	if !ok {
+		if l, ok := sv.value.(lengther); ok && name == sizeKey {
+			return ValueOf(l.Len())
 		}
 
-		st = st.Elem()
+		return nilValue
+	}
 
-		sr = sr.Elem()
-		if !sr.IsValid() {
-			return nilValue
-		}
+	sr := reflect.ValueOf(sv.value)
+	if m.method >= 0 {
+		return sv.invoke(name, sr.Method(m.method))
 	}
 
-	if _, ok := st.MethodByName(name); ok {
-		m := sr.MethodByName(name)
-		return sv.invoke(m)
+	if sr.Kind() == reflect.Ptr {
+		sr = sr.Elem()
 	}
 
-	if field, ok := sv.findField(name); ok {
-		fv := sr.FieldByName(field.Name)
-		if fv.Kind() == reflect.Func {
-			return sv.invoke(fv)
-		}
+	// This fails if the field is promoted through a nil embedded pointer.
+	fv, err := sr.FieldByIndexErr(m.field)
+	if err != nil {
+		return nilValue
+	}
 
-		return ValueOf(fv.Interface())
+	if fv.Kind() == reflect.Func {
+		return sv.invoke(name, fv)
 	}
 
-	return nilValue
+	return ValueOf(fv.Interface())
 }
 
 const tagKey = "liquid"
 
-// like FieldByName, but obeys `liquid:"name"` tags
-func (sv structValue) findField(name string) (*reflect.StructField, bool) {
-	sr := reflect.TypeOf(sv.value)
-	if sr.Kind() == reflect.Ptr {
-		sr = sr.Elem()
-	}
-
-	if field, ok := sr.FieldByName(name); ok {
-		if _, ok := field.Tag.Lookup(tagKey); !ok {
-			return &field, true
-		}
-	}
-
-	for i, n := 0, sr.NumField(); i < n; i++ {
-		field := sr.Field(i)
-		if field.Tag.Get(tagKey) == name {
-			return &field, true
-		}
+// lookup returns the field or method that templates access as name. A field
+// tagged `liquid:"name"` is accessed by the tag name, instead of its field name.
+func (sv structValue) lookup(name string) (structMember, bool) {
+	naming := sv.naming
+	if naming == nil {
+		naming = defaultStructNaming
 	}
 
-	return nil, false
+	return naming.lookup(reflect.TypeOf(sv.value), name)
 }
 
-func (sv structValue) invoke(fv reflect.Value) Value {
+func (sv structValue) invoke(name string, fv reflect.Value) Value {
 	if fv.IsNil() {
 		return nilValue
 	}
@@ -111,10 +104,45 @@ func (sv structValue) invoke(fv reflect.Value) Value {
 		return nilValue
 	}
 
-	results := fv.Call([]reflect.Value{})
+	var results []reflect.Value
+	if sv.methodPanics {
+		results = callMethod(name, fv)
+	} else {
+		results = fv.Call([]reflect.Value{})
+	}
+
 	if len(results) > 1 && !results[1].IsNil() {
 		panic(results[1].Interface())
 	}
 
 	return ValueOf(results[0].Interface())
 }
+
+// WithMethodPanics returns v, with a panic in a method that implements one of its
+// struct properties re-panicked as a MethodPanic, so that the caller can recover
+// from it. Otherwise, the panic propagates with its original value.
+func WithMethodPanics(v Value) Value {
+	switch sv := v.(type) {
+	case structValue:
+		sv.methodPanics = true
+		return sv
+	case customValue:
+		sv.base = WithMethodPanics(sv.base)
+		return sv
+	case *dropWrapper:
+		return WithMethodPanics(sv.Resolve())
+	default:
+		return v
+	}
+}
+
+// callMethod calls fv, re-panicking with a MethodPanic if it panics.
+func callMethod(name string, fv reflect.Value) []reflect.Value {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(MethodPanic{name, r})
+		}
+	}()
+
+	return fv.Call([]reflect.Value{})
+}
@@ -2,6 +2,8 @@ package values
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"strings"
 
@@ -52,6 +54,15 @@ func ValueOf(value any) Value { //nolint: gocyclo
 		return v
 	}
 
+	if isCustom(value) {
+		return customValue{value, kindValue(value)}
+	}
+
+	return kindValue(value)
+}
+
+// kindValue returns a Value that wraps its argument, according to its kind.
+func kindValue(value any) Value {
 	switch reflect.TypeOf(value).Kind() {
 	case reflect.Ptr:
 		rv := reflect.ValueOf(value)
@@ -60,18 +71,18 @@ func ValueOf(value any) Value { //nolint: gocyclo
 		}
 
 		if rv.Type().Elem().Kind() == reflect.Struct {
-			return structValue{wrapperValue{value}}
+			return structValue{wrapperValue: wrapperValue{value}}
 		}
 
 		return ValueOf(rv.Elem().Interface())
 	case reflect.String:
-		return stringValue{wrapperValue{value}}
+		return stringValue{wrapperValue: wrapperValue{value}}
 	case reflect.Array, reflect.Slice:
 		return arrayValue{wrapperValue{value}}
 	case reflect.Map:
 		return mapValue{wrapperValue{value}}
 	case reflect.Struct:
-		return structValue{wrapperValue{value}}
+		return structValue{wrapperValue: wrapperValue{value}}
 	default:
 		return wrapperValue{value}
 	}
@@ -126,7 +137,10 @@ var (
 type (
 	arrayValue  struct{ wrapperValue }
 	mapValue    struct{ wrapperValue }
-	stringValue struct{ wrapperValue }
+	stringValue struct {
+		wrapperValue
+		chars CharacterMode // what size, first and last count as characters
+	}
 )
 
 func (av arrayValue) Contains(ev Value) bool {
@@ -146,28 +160,54 @@ func (av arrayValue) Contains(ev Value) bool {
 func (av arrayValue) IndexValue(iv Value) Value {
 	ar := reflect.ValueOf(av.value)
 
-	var n int
-	switch ix := iv.Interface().(type) {
-	case int:
-		n = ix
-	case float32:
-		// Ruby array indexing truncates floats
-		n = int(ix)
-	case float64:
-		n = int(ix)
-	default:
-		return nilValue
+	if i, ok := sequenceIndex(iv.Interface(), ar.Len()); ok {
+		return ValueOf(ar.Index(i).Interface())
 	}
 
-	if n < 0 {
-		n += ar.Len()
+	return nilValue
+}
+
+// sequenceIndex returns the position that index selects in a sequence of length
+// n, and whether it is within the sequence. As in Ruby, a negative index counts
+// from the end, and a float index is truncated toward zero.
+func sequenceIndex(index any, n int) (int, bool) {
+	if index == nil {
+		return 0, false
+	}
+
+	var i int
+
+	rv := reflect.ValueOf(index)
+	switch {
+	case isIntKind(rv.Kind()):
+		i = int(rv.Int())
+	case isUintKind(rv.Kind()):
+		if rv.Uint() > uint64(n) {
+			return 0, false
+		}
+
+		i = int(rv.Uint()) // #nosec G115 -- bounded by n
+	case isFloatKind(rv.Kind()):
+		f := math.Trunc(rv.Float())
+		if math.IsNaN(f) || math.Abs(f) > float64(n) {
+			return 0, false
+		}
+
+		i = int(f)
+	default:
+		b, ok := index.(*big.Int)
+		if !ok || !b.IsInt64() {
+			return 0, false
+		}
+
+		i = int(b.Int64())
 	}
 
-	if 0 <= n && n < ar.Len() {
-		return ValueOf(ar.Index(n).Interface())
+	if i < 0 {
+		i += n
 	}
 
-	return nilValue
+	return i, 0 <= i && i < n
 }
 
 func (av arrayValue) PropertyValue(iv Value) Value {
@@ -205,7 +245,9 @@ func (mv mapValue) IndexValue(iv Value) Value {
 	ir := reflect.ValueOf(iv.Interface())
 
 	kt := mr.Type().Key()
-	if ir.IsValid() && ir.Type().ConvertibleTo(kt) && ir.Type().Comparable() {
+	if ir.IsValid() && ir.Type().ConvertibleTo(kt) && ir.Type().Comparable() &&
+		// Go converts an integer to the string of that code point; Ruby doesn't
+		(kt.Kind() != reflect.String || ir.Kind() == reflect.String) {
 		er := mr.MapIndex(ir.Convert(kt))
 		if er.IsValid() {
 			return ValueOf(er.Interface())
@@ -229,6 +271,18 @@ func (mv mapValue) PropertyValue(iv Value) Value {
 		return ValueOf(er.Interface())
 	case iv.Interface() == sizeKey:
 		return ValueOf(mr.Len())
+	case iv.Interface() == firstKey && mr.Len() > 0:
+		// A Go map has no order, so its first entry is the one with the least key.
+		keys := mr.MapKeys()
+
+		first := keys[0]
+		for _, k := range keys[1:] {
+			if Less(k.Interface(), first.Interface()) {
+				first = k
+			}
+		}
+
+		return ValueOf([]any{first.Interface(), mr.MapIndex(first).Interface()})
 	default:
 		return nilValue
 	}
@@ -244,8 +298,26 @@ func (sv stringValue) Contains(substr Value) bool {
 }
 
 func (sv stringValue) PropertyValue(iv Value) Value {
-	if iv.Interface() == sizeKey {
-		return ValueOf(len(sv.value.(string)))
+	s := reflect.ValueOf(sv.value).String()
+
+	switch iv.Interface() {
+	case sizeKey:
+		return ValueOf(sv.chars.Count(s))
+	case firstKey:
+		// As in Shopify Liquid, the first and last characters of "" are "".
+		if s == "" {
+			return ValueOf("")
+		}
+
+		return ValueOf(s[:sv.chars.next(s)])
+	case lastKey:
+		if s == "" {
+			return ValueOf("")
+		}
+
+		chars := sv.chars.Characters(s)
+
+		return ValueOf(chars[len(chars)-1])
 	}
 
 	return nilValue
@@ -1,6 +1,8 @@
 package values
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	yaml "gopkg.in/yaml.v2"
@@ -124,6 +126,94 @@ func TestValue_PropertyValue(t *testing.T) {
 	require.Nil(t, msv.PropertyValue(ValueOf(nil)).Interface())
 }
 
+// A queue has a length method, and a struct with a size field shadows it.
+type (
+	queue     struct{ items []int }
+	sizedList struct {
+		Size  int `liquid:"size"`
+		items []int
+	}
+)
+
+func (q queue) Len() int     { return len(q.items) }
+func (l sizedList) Len() int { return len(l.items) }
+
+// TestValue_lookupRules checks the rows of the table in the package documentation.
+func TestValue_lookupRules(t *testing.T) {
+	var (
+		array = []string{"a", "b", "c"}
+		hash  = map[string]any{"b": 2, "a": 1}
+		big1  = new(big.Int).Lsh(big.NewInt(1), 100)
+	)
+
+	tests := []struct {
+		value    any
+		property string // x.name, if not empty
+		index    any    // x[key], otherwise
+		expected any
+	}{
+		{array, "", 1, "b"},
+		{array, "", -1, "c"},
+		{array, "", -3, "a"},
+		{array, "", -4, nil},
+		{array, "", 3, nil},
+		{array, "", int64(1), "b"},
+		{array, "", uint8(2), "c"},
+		{array, "", 1.9, "b"},
+		{array, "", -1.5, "c"},
+		{array, "", -0.5, "a"},
+		{array, "", math.NaN(), nil},
+		{array, "", math.Inf(1), nil},
+		{array, "", big.NewInt(1), "b"},
+		{array, "", big1, nil},
+		{array, "", "1", nil},
+		{array, "", "first", nil},
+		{array, "size", nil, 3},
+		{array, "first", nil, "a"},
+		{array, "last", nil, "c"},
+		{NewRange(1, 5), "", -1.5, 5},
+
+		{hash, "a", nil, 1},
+		{hash, "", "a", 1},
+		{hash, "", "size", nil},
+		{hash, "size", nil, 2},
+		{hash, "first", nil, []any{"a", 1}},
+		{hash, "last", nil, nil},
+		{map[string]any{"first": "own"}, "first", nil, "own"},
+		{map[string]any{"A": 1}, "", 65, nil},
+		{map[int]string{65: "A"}, "", 65, "A"},
+		{map[string]any{}, "first", nil, nil},
+		{yaml.MapSlice{{Key: "b", Value: 2}, {Key: "a", Value: 1}}, "first", nil, []any{"b", 2}},
+
+		{"héllo", "size", nil, 5},
+		{"héllo", "first", nil, "h"},
+		{"héllo", "last", nil, "o"},
+		{"", "first", nil, ""},
+		{"", "last", nil, ""},
+		{"héllo", "", 0, nil},
+
+		{queue{[]int{1, 2}}, "size", nil, 2},
+		{&queue{[]int{1, 2}}, "size", nil, 2},
+		{queue{[]int{1, 2}}, "", "size", 2},
+		{sizedList{Size: 10, items: []int{1}}, "size", nil, 10},
+		{struct{}{}, "size", nil, nil},
+
+		{12, "size", nil, nil},
+		{nil, "first", nil, nil},
+	}
+
+	for _, test := range tests {
+		v := ValueOf(test.value)
+		if test.property != "" {
+			require.Equal(t, test.expected, v.PropertyValue(ValueOf(test.property)).Interface(), "%#v.%s", test.value, test.property)
+		} else {
+			require.Equal(t, test.expected, v.IndexValue(ValueOf(test.index)).Interface(), "%#v[%v]", test.value, test.index)
+		}
+	}
+
+	require.Equal(t, 2, Length(queue{[]int{1, 2}}))
+}
+
 func TestValue_Contains(t *testing.T) {
 	// array
 	require.True(t, ValueOf([]int{1, 2}).Contains(ValueOf(2)))